@@ -0,0 +1,188 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// AccountType describes what kind of account an address is
+type AccountType string
+
+const (
+	// AccountEOA is an externally owned account, which has no code
+	AccountEOA AccountType = "eoa"
+	// AccountContract is a smart contract, such as a smart contract wallet
+	AccountContract AccountType = "contract"
+	// AccountDelegated is an externally owned account whose code is an EIP-7702 delegation designator
+	AccountDelegated AccountType = "eip7702-delegated"
+	// AccountUndeployed is a counterfactual wallet, which has no code yet but signs ERC6492 signatures
+	AccountUndeployed AccountType = "undeployed"
+)
+
+// delegationPrefix is the prefix of the EIP-7702 delegation designator 0xef0100 || address
+var delegationPrefix = []byte{0xef, 0x01, 0x00}
+
+// Account is the classification of an address
+type Account struct {
+	Address ethcommon.Address
+	Type    AccountType
+	// Delegate is the address that an AccountDelegated delegates its code to,
+	// it is the zero address for the other types
+	Delegate ethcommon.Address
+}
+
+// AccountCache caches the classification of addresses, so that the code of an address
+// is not fetched again for every signature it signs. It must be safe for concurrent use.
+type AccountCache interface {
+	GetAccount(address ethcommon.Address) (*Account, bool)
+	SetAccount(account *Account)
+}
+
+// ClassifyAccount classifies address by its code, look up AccountType for the types.
+// signature may be nil, when it is an ERC6492 signature an address without code is AccountUndeployed instead of AccountEOA.
+// The code is read on the state configured by WithBlockNumber, WithBlockHash or WithPendingState,
+// and the cache given by WithAccountCache is only used for the latest state.
+func ClassifyAccount(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, signature []byte, opts ...VerifyOption) (*Account, error) {
+	return classifyAccount(ctx, caller, address, signature, newVerifyOptions(opts))
+}
+
+func classifyAccount(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, signature []byte, options *verifyOptions) (*Account, error) {
+	cache := options.accountCache
+	if options.block != (BlockReference{}) {
+		cache = nil
+	}
+	var (
+		account *Account
+		ok      bool
+	)
+	if cache != nil {
+		account, ok = cache.GetAccount(address)
+	}
+	if !ok {
+		pinned, err := pinContractCaller(caller, options.block)
+		if err != nil {
+			return nil, err
+		}
+		code, err := pinned.CodeAt(ctx, address, nil)
+		if err != nil {
+			return nil, err
+		}
+		account = classifyCode(address, code)
+		if cache != nil {
+			cache.SetAccount(account)
+		}
+	}
+	if account.Type == AccountEOA && IsERC6492Signature(signature) {
+		return &Account{Address: address, Type: AccountUndeployed}, nil
+	}
+	return account, nil
+}
+
+// classifyCode classifies address by its code
+func classifyCode(address ethcommon.Address, code []byte) *Account {
+	switch {
+	case len(code) == 0:
+		return &Account{Address: address, Type: AccountEOA}
+	case len(code) == len(delegationPrefix)+ethcommon.AddressLength && bytes.HasPrefix(code, delegationPrefix):
+		return &Account{Address: address, Type: AccountDelegated, Delegate: ethcommon.BytesToAddress(code[len(delegationPrefix):])}
+	default:
+		return &Account{Address: address, Type: AccountContract}
+	}
+}
+
+// MemoryAccountCache is an in-memory AccountCache
+type MemoryAccountCache struct {
+	ttl     time.Duration
+	mu      sync.RWMutex
+	entries map[ethcommon.Address]memoryAccountEntry
+	now     func() time.Time
+	// inserts counts the calls of SetAccount since the last pruning
+	inserts int
+}
+
+// memoryAccountCachePruneInterval is the number of SetAccount calls between two prunings of the expired entries,
+// which keeps the cost of pruning constant per insert
+const memoryAccountCachePruneInterval = 1024
+
+type memoryAccountEntry struct {
+	account   Account
+	expiresAt time.Time
+}
+
+func (e memoryAccountEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// NewMemoryAccountCache creates an in-memory AccountCache whose entries expire after ttl, 0 means never.
+// An EOA may get code by EIP-7702 and a counterfactual wallet may be deployed at any time,
+// a short ttl keeps the cache from hiding such changes for long.
+// The expired entries are deleted when they are looked up, and the others are pruned once every 1024 accounts set.
+func NewMemoryAccountCache(ttl time.Duration) *MemoryAccountCache {
+	return &MemoryAccountCache{
+		ttl:     ttl,
+		entries: make(map[ethcommon.Address]memoryAccountEntry),
+		now:     time.Now,
+	}
+}
+
+// GetAccount implements AccountCache
+func (c *MemoryAccountCache) GetAccount(address ethcommon.Address) (*Account, bool) {
+	now := c.now()
+	c.mu.RLock()
+	entry, ok := c.entries[address]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	if entry.expired(now) {
+		c.mu.Lock()
+		// the entry may have been set again in the meantime
+		if entry, ok := c.entries[address]; ok && entry.expired(now) {
+			delete(c.entries, address)
+		}
+		c.mu.Unlock()
+		return nil, false
+	}
+	account := entry.account
+	return &account, true
+}
+
+// SetAccount implements AccountCache
+func (c *MemoryAccountCache) SetAccount(account *Account) {
+	now := c.now()
+	entry := memoryAccountEntry{account: *account}
+	if c.ttl > 0 {
+		entry.expiresAt = now.Add(c.ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inserts++
+	if c.ttl > 0 && c.inserts >= memoryAccountCachePruneInterval {
+		c.inserts = 0
+		for address, e := range c.entries {
+			if e.expired(now) {
+				delete(c.entries, address)
+			}
+		}
+	}
+	c.entries[account.Address] = entry
+}
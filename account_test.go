@@ -0,0 +1,320 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingCaller counts the calls made through a bind.ContractCaller
+type countingCaller struct {
+	bind.ContractCaller
+	codeAt       int
+	callContract int
+}
+
+func (c *countingCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	c.codeAt++
+	return c.ContractCaller.CodeAt(ctx, contract, blockNumber)
+}
+
+func (c *countingCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.callContract++
+	return c.ContractCaller.CallContract(ctx, call, blockNumber)
+}
+
+func TestClassifyAccount(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	counterfactual, calldata := wallets.counterfactual(t, wallets.eoa)
+	wrapped, err := WrapERC6492Signature(wallets.factory, calldata, wallets.sign(t, []byte("hello")))
+	assert.NoError(t, err)
+	delegate := common.HexToAddress("0x63c0c19a282a1B52b07dD5a65b58948A07DAE32B")
+
+	tests := []struct {
+		name      string
+		caller    bind.ContractCaller
+		address   common.Address
+		signature []byte
+		want      Account
+	}{
+		{
+			name:    "eoa",
+			caller:  wallets.backend,
+			address: wallets.eoa,
+			want:    Account{Address: wallets.eoa, Type: AccountEOA},
+		},
+		{
+			name:    "contract",
+			caller:  wallets.backend,
+			address: wallets.owner,
+			want:    Account{Address: wallets.owner, Type: AccountContract},
+		},
+		{
+			name:      "undeployed",
+			caller:    wallets.backend,
+			address:   counterfactual,
+			signature: wrapped,
+			want:      Account{Address: counterfactual, Type: AccountUndeployed},
+		},
+		{
+			name:      "contract with ERC6492 signature",
+			caller:    wallets.backend,
+			address:   wallets.owner,
+			signature: wrapped,
+			want:      Account{Address: wallets.owner, Type: AccountContract},
+		},
+		{
+			name:    "eip7702 delegated",
+			caller:  &mockContractCaller{code: append([]byte{0xef, 0x01, 0x00}, delegate[:]...)},
+			address: wallets.eoa,
+			want:    Account{Address: wallets.eoa, Type: AccountDelegated, Delegate: delegate},
+		},
+		{
+			name:    "contract starting with 0xef0100",
+			caller:  &mockContractCaller{code: append([]byte{0xef, 0x01, 0x00}, delegate[:4]...)},
+			address: wallets.eoa,
+			want:    Account{Address: wallets.eoa, Type: AccountContract},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ClassifyAccount(context.Background(), tt.caller, tt.address, tt.signature)
+			assert.NoError(t, err)
+			assert.Equal(t, &tt.want, got)
+		})
+	}
+}
+
+func TestMemoryAccountCache(t *testing.T) {
+	cache := NewMemoryAccountCache(0)
+	account := &Account{Address: common.Address{1}, Type: AccountContract}
+	cache.SetAccount(account)
+	got, ok := cache.GetAccount(account.Address)
+	assert.True(t, ok)
+	assert.Equal(t, account, got)
+	_, ok = cache.GetAccount(common.Address{2})
+	assert.False(t, ok)
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiring := NewMemoryAccountCache(time.Minute)
+	expiring.now = func() time.Time { return now }
+	expiring.SetAccount(account)
+	expiring.SetAccount(&Account{Address: common.Address{2}, Type: AccountEOA})
+	now = now.Add(2 * time.Minute)
+	_, ok = expiring.GetAccount(account.Address)
+	assert.False(t, ok)
+	assert.Len(t, expiring.entries, 1)
+
+	// the expired entry of common.Address{2} is pruned by the insert that completes the interval
+	for i := 0; i < memoryAccountCachePruneInterval-3; i++ {
+		expiring.SetAccount(&Account{Address: common.Address{3}, Type: AccountEOA})
+	}
+	assert.Len(t, expiring.entries, 2)
+	expiring.SetAccount(&Account{Address: common.Address{3}, Type: AccountEOA})
+	assert.Len(t, expiring.entries, 1)
+	_, ok = expiring.GetAccount(common.Address{3})
+	assert.True(t, ok)
+}
+
+func TestVerifySignatureExWithStrategy(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	data := []byte("hello")
+	signature := wallets.sign(t, data)
+	otherSignature := MustMustHexDecode(t, "0x0498c6564863c78e663848b963fde1ea1d860d5d882d2abdb707d1e9179ff80630a4a71705da534a562c08cb64a546c6132de26eb77a44f086832cbc1dbe01f71b")
+	counterfactual, calldata := wallets.counterfactual(t, wallets.eoa)
+	wrapped, err := WrapERC6492Signature(wallets.factory, calldata, signature)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		address          common.Address
+		signature        []byte
+		want             bool
+		wantMethod       VerificationMethod
+		wantType         AccountType
+		wantCallContract int
+		wantFailures     int
+	}{
+		{
+			name:       "eoa",
+			address:    wallets.eoa,
+			signature:  signature,
+			want:       true,
+			wantMethod: MethodECDSA,
+			wantType:   AccountEOA,
+		},
+		{
+			name:         "eoa/signed by another key",
+			address:      wallets.eoa,
+			signature:    otherSignature,
+			wantType:     AccountEOA,
+			wantFailures: 1,
+		},
+		{
+			name:             "contract",
+			address:          wallets.owner,
+			signature:        signature,
+			want:             true,
+			wantMethod:       MethodERC1271,
+			wantType:         AccountContract,
+			wantCallContract: 1,
+		},
+		{
+			name:             "undeployed",
+			address:          counterfactual,
+			signature:        wrapped,
+			want:             true,
+			wantMethod:       MethodERC6492,
+			wantType:         AccountUndeployed,
+			wantCallContract: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller := &countingCaller{ContractCaller: wallets.backend}
+			result, err := VerifySignatureExDetailed(context.Background(), caller, tt.address, data, tt.signature, WithStrategy(StrategyClassifyAccount))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result.Valid)
+			assert.Equal(t, tt.wantMethod, result.Method)
+			assert.Equal(t, tt.wantType, result.Account.Type)
+			assert.Len(t, result.Failures, tt.wantFailures)
+			assert.Equal(t, 1, caller.codeAt)
+			assert.Equal(t, tt.wantCallContract, caller.callContract)
+		})
+	}
+
+	// the classification is fetched once with a cache
+	caller := &countingCaller{ContractCaller: wallets.backend}
+	cache := NewMemoryAccountCache(time.Minute)
+	for i := 0; i < 3; i++ {
+		valid, err := VerifySignatureEx(context.Background(), caller, wallets.owner, data, signature, WithStrategy(StrategyClassifyAccount), WithAccountCache(cache))
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	}
+	assert.Equal(t, 1, caller.codeAt)
+	assert.Equal(t, 3, caller.callContract)
+}
+
+// delegatedCaller emulates an EIP-7702 delegated EOA on top of the simulated backend,
+// the calls to the EOA run the code of the delegate
+type delegatedCaller struct {
+	bind.ContractCaller
+	eoa      common.Address
+	delegate common.Address
+}
+
+func (d *delegatedCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	if contract == d.eoa {
+		return append([]byte{0xef, 0x01, 0x00}, d.delegate[:]...), nil
+	}
+	return d.ContractCaller.CodeAt(ctx, contract, blockNumber)
+}
+
+func (d *delegatedCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if call.To != nil && *call.To == d.eoa {
+		call.To = &d.delegate
+	}
+	return d.ContractCaller.CallContract(ctx, call, blockNumber)
+}
+
+func TestVerifySignatureExWithDelegationPolicy(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	caller := &delegatedCaller{ContractCaller: wallets.backend, eoa: wallets.eoa, delegate: wallets.alwaysOK}
+	data := []byte("hello")
+	signature := wallets.sign(t, data)
+	otherSignature := MustMustHexDecode(t, "0x0498c6564863c78e663848b963fde1ea1d860d5d882d2abdb707d1e9179ff80630a4a71705da534a562c08cb64a546c6132de26eb77a44f086832cbc1dbe01f71b")
+
+	tests := []struct {
+		name       string
+		signature  []byte
+		opts       []VerifyOption
+		want       bool
+		wantMethod VerificationMethod
+	}{
+		{
+			name:       "either/key",
+			signature:  signature,
+			want:       true,
+			wantMethod: MethodECDSA,
+		},
+		{
+			name:       "either/delegate",
+			signature:  otherSignature,
+			want:       true,
+			wantMethod: MethodERC1271,
+		},
+		{
+			name:       "either/classified",
+			signature:  otherSignature,
+			opts:       []VerifyOption{WithStrategy(StrategyClassifyAccount)},
+			want:       true,
+			wantMethod: MethodERC1271,
+		},
+		{
+			name:       "ecdsa only/key",
+			signature:  signature,
+			opts:       []VerifyOption{WithDelegationPolicy(DelegationECDSAOnly)},
+			want:       true,
+			wantMethod: MethodECDSA,
+		},
+		{
+			name:      "ecdsa only/delegate",
+			signature: otherSignature,
+			opts:      []VerifyOption{WithDelegationPolicy(DelegationECDSAOnly)},
+		},
+		{
+			name:       "erc1271 only/key",
+			signature:  signature,
+			opts:       []VerifyOption{WithDelegationPolicy(DelegationERC1271Only)},
+			want:       true,
+			wantMethod: MethodERC1271,
+		},
+		{
+			name:       "erc1271 only/delegate",
+			signature:  otherSignature,
+			opts:       []VerifyOption{WithDelegationPolicy(DelegationERC1271Only), WithStrategy(StrategyClassifyAccount)},
+			want:       true,
+			wantMethod: MethodERC1271,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := VerifySignatureExDetailed(context.Background(), caller, wallets.eoa, data, tt.signature, tt.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result.Valid)
+			assert.Equal(t, tt.wantMethod, result.Method)
+			if len(tt.opts) > 0 {
+				assert.Equal(t, &Account{Address: wallets.eoa, Type: AccountDelegated, Delegate: wallets.alwaysOK}, result.Account)
+			} else {
+				assert.Nil(t, result.Account)
+			}
+		})
+	}
+
+	// the policy does not affect the accounts that are not delegated
+	result, err := VerifySignatureExDetailed(context.Background(), wallets.backend, wallets.owner, data, signature, WithDelegationPolicy(DelegationECDSAOnly))
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, MethodERC1271, result.Method)
+	assert.Equal(t, AccountContract, result.Account.Type)
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// BlockHashContractCaller is implemented by backends that can perform contract calls
+// on the state of a block selected by its hash (EIP-1898), such as *ethclient.Client.
+type BlockHashContractCaller interface {
+	CallContractAtHash(ctx context.Context, call ethereum.CallMsg, blockHash ethcommon.Hash) ([]byte, error)
+}
+
+// BlockReference is the block whose state the contract calls are performed on.
+// The zero value refers to the latest block.
+type BlockReference struct {
+	// Number is the block number, it is nil when the block is selected by hash or the state is pending
+	Number *big.Int
+	// Hash is the block hash, it is the zero hash when the block is not selected by hash
+	Hash ethcommon.Hash
+	// Pending reports whether the calls are performed on the pending state
+	Pending bool
+}
+
+// String returns "latest", "pending", the decimal block number or the hex block hash
+func (b BlockReference) String() string {
+	switch {
+	case b.Pending:
+		return "pending"
+	case b.Hash != (ethcommon.Hash{}):
+		return b.Hash.Hex()
+	case b.Number != nil:
+		return b.Number.String()
+	default:
+		return "latest"
+	}
+}
+
+// pinnedContractCaller performs every call of the wrapped caller on the state of block,
+// the block arguments of CodeAt and CallContract are ignored.
+type pinnedContractCaller struct {
+	caller bind.ContractCaller
+	block  BlockReference
+}
+
+// pinContractCaller returns a bind.ContractCaller that performs every call on the state of block.
+// bind.ErrNoPendingState or ErrNoBlockHashState is returned if caller does not support the block.
+func pinContractCaller(caller bind.ContractCaller, block BlockReference) (bind.ContractCaller, error) {
+	switch {
+	case block.Pending:
+		if _, ok := caller.(bind.PendingContractCaller); !ok {
+			return nil, bind.ErrNoPendingState
+		}
+	case block.Hash != (ethcommon.Hash{}):
+		if _, ok := caller.(BlockHashContractCaller); !ok {
+			return nil, ErrNoBlockHashState
+		}
+	case block.Number == nil:
+		return caller, nil
+	}
+	return &pinnedContractCaller{caller: caller, block: block}, nil
+}
+
+// CodeAt implements bind.ContractCaller
+func (p *pinnedContractCaller) CodeAt(ctx context.Context, contract ethcommon.Address, _ *big.Int) ([]byte, error) {
+	switch {
+	case p.block.Pending:
+		return p.caller.(bind.PendingContractCaller).PendingCodeAt(ctx, contract)
+	case p.block.Hash != (ethcommon.Hash{}):
+		// eth_getCode does not accept a block hash everywhere, the code is read by a deployless call instead
+		return p.CallContract(ctx, ethereum.CallMsg{Data: codeReaderInput(contract)}, nil)
+	default:
+		return p.caller.CodeAt(ctx, contract, p.block.Number)
+	}
+}
+
+// CallContract implements bind.ContractCaller
+func (p *pinnedContractCaller) CallContract(ctx context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	switch {
+	case p.block.Pending:
+		return p.caller.(bind.PendingContractCaller).PendingCallContract(ctx, call)
+	case p.block.Hash != (ethcommon.Hash{}):
+		return p.caller.(BlockHashContractCaller).CallContractAtHash(ctx, call, p.block.Hash)
+	default:
+		return p.caller.CallContract(ctx, call, p.block.Number)
+	}
+}
+
+// codeReaderInput returns the init code that returns the code of contract, which is
+//
+//	PUSH20 contract EXTCODESIZE DUP1 PUSH1 0 DUP1 PUSH20 contract EXTCODECOPY PUSH1 0 RETURN
+func codeReaderInput(contract ethcommon.Address) []byte {
+	input := append([]byte{0x73}, contract[:]...)
+	input = append(input, 0x3b, 0x80, 0x60, 0x00, 0x80, 0x73)
+	input = append(input, contract[:]...)
+	return append(input, 0x3c, 0x60, 0x00, 0xf3)
+}
@@ -0,0 +1,147 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/storyicon/sigverify/contracts/erc1271"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockHashCaller adds CallContractAtHash to the simulated backend, which only serves the latest block
+type blockHashCaller struct {
+	*backends.SimulatedBackend
+}
+
+func (b *blockHashCaller) CallContractAtHash(ctx context.Context, call ethereum.CallMsg, blockHash common.Hash) ([]byte, error) {
+	if blockHash != b.Blockchain().CurrentBlock().Hash() {
+		return nil, errors.New("header not found")
+	}
+	return b.CallContract(ctx, call, nil)
+}
+
+func TestBlockReferenceString(t *testing.T) {
+	hash := common.HexToHash("0x01")
+	assert.Equal(t, "latest", BlockReference{}.String())
+	assert.Equal(t, "pending", BlockReference{Pending: true}.String())
+	assert.Equal(t, "15000000", BlockReference{Number: big.NewInt(15000000)}.String())
+	assert.Equal(t, hash.Hex(), BlockReference{Hash: hash}.String())
+}
+
+func TestVerifyERC1271SignatureAtBlock(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	data := []byte("hello")
+	signature := wallets.sign(t, data)
+	head := wallets.backend.Blockchain().CurrentBlock()
+
+	// a wallet that is only deployed in the pending state
+	pending, _, _, err := erc1271.DeployMockERC1271Owner(wallets.auth, wallets.backend, wallets.eoa)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		caller    bind.ContractCaller
+		address   common.Address
+		opts      []VerifyOption
+		want      bool
+		wantBlock BlockReference
+		wantErr   error
+	}{
+		{
+			name:    "latest",
+			caller:  wallets.backend,
+			address: wallets.owner,
+			want:    true,
+		},
+		{
+			name:      "block number",
+			caller:    wallets.backend,
+			address:   wallets.owner,
+			opts:      []VerifyOption{WithBlockNumber(head.Number())},
+			want:      true,
+			wantBlock: BlockReference{Number: head.Number()},
+		},
+		{
+			name:      "block hash",
+			caller:    &blockHashCaller{wallets.backend},
+			address:   wallets.owner,
+			opts:      []VerifyOption{WithBlockHash(head.Hash())},
+			want:      true,
+			wantBlock: BlockReference{Hash: head.Hash()},
+		},
+		{
+			name:      "block hash/eoa",
+			caller:    &blockHashCaller{wallets.backend},
+			address:   wallets.eoa,
+			opts:      []VerifyOption{WithBlockHash(head.Hash())},
+			wantBlock: BlockReference{Hash: head.Hash()},
+			wantErr:   ErrNoContractCode,
+		},
+		{
+			name:      "block hash/not supported",
+			caller:    wallets.backend,
+			address:   wallets.owner,
+			opts:      []VerifyOption{WithBlockHash(head.Hash())},
+			wantBlock: BlockReference{Hash: head.Hash()},
+			wantErr:   ErrNoBlockHashState,
+		},
+		{
+			name:    "pending wallet/latest",
+			caller:  wallets.backend,
+			address: pending,
+			wantErr: ErrNoContractCode,
+		},
+		{
+			name:      "pending wallet/pending",
+			caller:    wallets.backend,
+			address:   pending,
+			opts:      []VerifyOption{WithPendingState()},
+			want:      true,
+			wantBlock: BlockReference{Pending: true},
+		},
+		{
+			name:      "pending/not supported",
+			caller:    &mockContractCaller{},
+			address:   pending,
+			opts:      []VerifyOption{WithPendingState()},
+			wantBlock: BlockReference{Pending: true},
+			wantErr:   bind.ErrNoPendingState,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := VerifyERC1271SignatureDetailed(context.Background(), tt.caller, tt.address, data, signature, tt.opts...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, result.Valid)
+			assert.Equal(t, &tt.wantBlock, result.Block)
+		})
+	}
+
+	// the simulated backend only serves the latest block
+	_, err = VerifyERC1271Signature(context.Background(), wallets.backend, wallets.owner, data, signature, WithBlockNumber(big.NewInt(0)))
+	assert.Error(t, err)
+}
@@ -0,0 +1,103 @@
+pragma solidity ^0.8.7;
+
+// Mock ERC1271 wallets used by the tests of sigverify.
+// They are intentionally minimal and must not be used to hold any funds.
+
+// bytes4(keccak256("isValidSignature(bytes32,bytes)")
+bytes4 constant MAGICVALUE = 0x1626ba7e;
+
+// MockERC1271AlwaysValid accepts any signature
+contract MockERC1271AlwaysValid {
+    function isValidSignature(bytes32 _hash, bytes memory _signature) public pure returns (bytes4 magicValue) {
+        return MAGICVALUE;
+    }
+}
+
+// MockERC1271Owner accepts the 65-byte r || s || v signatures of its owner
+contract MockERC1271Owner {
+    address private owner;
+
+    constructor(address _owner) {
+        owner = _owner;
+    }
+
+    function isValidSignature(bytes32 _hash, bytes memory _signature) public view returns (bytes4 magicValue) {
+        if (_signature.length != 65) {
+            return 0xffffffff;
+        }
+        bytes32 r;
+        bytes32 s;
+        uint8 v;
+        assembly {
+            r := mload(add(_signature, 0x20))
+            s := mload(add(_signature, 0x40))
+            v := byte(0, mload(add(_signature, 0x60)))
+        }
+        if (ecrecover(_hash, v, r, s) != owner) {
+            return 0xffffffff;
+        }
+        return MAGICVALUE;
+    }
+}
+
+// MockERC1271Reverting reverts with a reason for any signature
+contract MockERC1271Reverting {
+    function isValidSignature(bytes32 _hash, bytes memory _signature) public pure returns (bytes4 magicValue) {
+        revert("MockERC1271: invalid signature");
+    }
+}
+
+// MockERC1271WrongMagic returns a value other than the ERC1271 magic for any signature
+contract MockERC1271WrongMagic {
+    function isValidSignature(bytes32 _hash, bytes memory _signature) public pure returns (bytes4 magicValue) {
+        return 0xdeadbeef;
+    }
+}
+
+// MockERC1271GasGuzzler never returns and consumes all the gas of the call
+contract MockERC1271GasGuzzler {
+    function isValidSignature(bytes32 _hash, bytes memory _signature) public pure returns (bytes4 magicValue) {
+        while (true) {}
+    }
+}
+
+// MockERC1271LegacyOwner implements the draft isValidSignature(bytes,bytes) of ERC1271 like the legacy Argent wallet,
+// it hashes _data itself and accepts the 65-byte r || s || v signatures of its owner over
+// keccak256("\x19Ethereum Signed Message:\n32" || keccak256(_data))
+contract MockERC1271LegacyOwner {
+    // bytes4(keccak256("isValidSignature(bytes,bytes)")
+    bytes4 constant internal LEGACY_MAGICVALUE = 0x20c13b0b;
+
+    address private owner;
+
+    constructor(address _owner) {
+        owner = _owner;
+    }
+
+    function isValidSignature(bytes memory _data, bytes memory _signature) public view returns (bytes4 magicValue) {
+        if (_signature.length != 65) {
+            return 0xffffffff;
+        }
+        bytes32 hash = keccak256(abi.encodePacked("\x19Ethereum Signed Message:\n32", keccak256(_data)));
+        bytes32 r;
+        bytes32 s;
+        uint8 v;
+        assembly {
+            r := mload(add(_signature, 0x20))
+            s := mload(add(_signature, 0x40))
+            v := byte(0, mload(add(_signature, 0x60)))
+        }
+        if (ecrecover(hash, v, r, s) != owner) {
+            return 0xffffffff;
+        }
+        return LEGACY_MAGICVALUE;
+    }
+}
+
+// MockERC1271Factory deploys MockERC1271Owner wallets with CREATE2, it is used as the
+// factory of ERC-6492 signatures of counterfactual wallets
+contract MockERC1271Factory {
+    function deploy(address _owner) public returns (address wallet) {
+        return address(new MockERC1271Owner{salt: bytes32(uint256(uint160(_owner)))}(_owner));
+    }
+}
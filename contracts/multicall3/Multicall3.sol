@@ -0,0 +1,18 @@
+pragma solidity ^0.8.7;
+
+// The subset of Multicall3 (https://github.com/mds1/multicall) used by sigverify,
+// which is deployed to 0xcA11bde05977b3631167028862bE2a173976CA11 on most chains.
+interface Multicall3 {
+    struct Call3 {
+        address target;
+        bool allowFailure;
+        bytes callData;
+    }
+
+    struct Result {
+        bool success;
+        bytes returnData;
+    }
+
+    function aggregate3(Call3[] calldata calls) external payable returns (Result[] memory returnData);
+}
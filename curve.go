@@ -15,81 +15,268 @@
 package sigverify
 
 import (
+	"crypto/ecdsa"
 	"fmt"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+var (
+	// secp256k1N is the order of the secp256k1 curve
+	secp256k1N = crypto.S256().Params().N
+	// secp256k1HalfN is secp256k1N / 2, the upper bound of canonical s values defined by EIP-2
+	secp256k1HalfN = new(big.Int).Div(secp256k1N, big.NewInt(2))
+)
+
 // VerifyEllipticCurveSignatureEx is used to verify elliptic curve signatures
 // It calls the EcRecoverEx function to verify the signature.
-func VerifyEllipticCurveSignatureEx(address ethcommon.Address, data []byte, signature []byte) (bool, error) {
-	recovered, err := EcRecoverEx(data, signature)
+func VerifyEllipticCurveSignatureEx(address ethcommon.Address, data []byte, signature []byte, opts ...RecoveryOption) (bool, error) {
+	result, err := VerifyEllipticCurveSignatureExDetailed(address, data, signature, opts...)
+	return result.Valid, err
+}
+
+// VerifyEllipticCurveSignatureExDetailed is like VerifyEllipticCurveSignatureEx,
+// but returns a VerificationResult that explains how the signature was verified.
+func VerifyEllipticCurveSignatureExDetailed(address ethcommon.Address, data []byte, signature []byte, opts ...RecoveryOption) (*VerificationResult, error) {
+	result := newVerificationResult(address, accounts.TextHash(data))
+	return result, verifyEllipticCurveDigest(result, signature, opts)
+}
+
+// verifyEllipticCurveDigest recovers the signer of result.Digest and compares it with result.Address,
+// the returned error is the same as the one returned by RecoverPublicKeyEx.
+func verifyEllipticCurveDigest(result *VerificationResult, signature []byte, opts []RecoveryOption) error {
+	sig, method, err := normalizeSignatureEx(signature, newRecoveryOptions(opts))
 	if err != nil {
-		return false, err
+		result.fail(method, err)
+		return err
 	}
-	return recovered == address, nil
+	pub, err := RecoverPublicKey(result.Digest[:], sig, opts...)
+	if err != nil {
+		result.fail(method, err)
+		return err
+	}
+	result.RecoveredAddress = crypto.PubkeyToAddress(*pub)
+	if result.RecoveredAddress != result.Address {
+		result.fail(method, fmt.Errorf("%w: recovered %s, expected %s", ErrAddressMismatch, result.RecoveredAddress.Hex(), result.Address.Hex()))
+		return nil
+	}
+	result.succeed(method)
+	return nil
 }
 
 // VerifyEllipticCurveHexSignatureEx is used to verify elliptic curve signatures
 // It calls the EcRecoverEx function to verify the signature.
-func VerifyEllipticCurveHexSignatureEx(address ethcommon.Address, data []byte, signature string) (bool, error) {
+func VerifyEllipticCurveHexSignatureEx(address ethcommon.Address, data []byte, signature string, opts ...RecoveryOption) (bool, error) {
 	sig, err := HexDecode(signature)
 	if err != nil {
 		return false, err
 	}
-	return VerifyEllipticCurveSignatureEx(address, data, sig)
+	return VerifyEllipticCurveSignatureEx(address, data, sig, opts...)
+}
+
+// VerifyEllipticCurveParsedSignatureEx is like VerifyEllipticCurveSignatureEx but accepts a parsed Signature
+func VerifyEllipticCurveParsedSignatureEx(address ethcommon.Address, data []byte, signature Signature, opts ...RecoveryOption) (bool, error) {
+	return VerifyEllipticCurveSignatureEx(address, data, signature.Bytes(), opts...)
 }
 
 // VerifyEllipticCurveSignature is used to verify the elliptic curve signature
 // It calls the native ecrecover function to verify the signature
-func VerifyEllipticCurveSignature(address ethcommon.Address, data []byte, signature []byte) (bool, error) {
-	recovered, err := EcRecover(data, signature)
+func VerifyEllipticCurveSignature(address ethcommon.Address, data []byte, signature []byte, opts ...RecoveryOption) (bool, error) {
+	recovered, err := EcRecover(data, signature, opts...)
 	if err != nil {
 		return false, err
 	}
 	return recovered == address, nil
 }
 
+// VerifyEllipticCurveParsedSignature is like VerifyEllipticCurveSignature but accepts a parsed Signature
+func VerifyEllipticCurveParsedSignature(address ethcommon.Address, data []byte, signature Signature, opts ...RecoveryOption) (bool, error) {
+	return VerifyEllipticCurveSignature(address, data, signature.Bytes(), opts...)
+}
+
 // EcRecoverEx is an extension to EcRecover that supports more signature formats, such as ledger signatures.
-func EcRecoverEx(data []byte, sig []byte) (ethcommon.Address, error) {
-	return RecoveryAddressEx(accounts.TextHash(data), sig)
+func EcRecoverEx(data []byte, sig []byte, opts ...RecoveryOption) (ethcommon.Address, error) {
+	return RecoveryAddressEx(accounts.TextHash(data), sig, opts...)
 }
 
-// RecoveryAddressEx is an extension to RecoveryAddress that supports more signature formats, such as ledger signatures.
-func RecoveryAddressEx(data []byte, sig []byte) (ethcommon.Address, error) {
+// EcRecoverParsedEx is like EcRecoverEx but accepts a parsed Signature
+func EcRecoverParsedEx(data []byte, sig Signature, opts ...RecoveryOption) (ethcommon.Address, error) {
+	return EcRecoverEx(data, sig.Bytes(), opts...)
+}
+
+// RecoveryAddressEx is an extension to RecoveryAddress that supports more signature formats, such as ledger signatures
+// and EIP-2098 compact signatures.
+func RecoveryAddressEx(data []byte, sig []byte, opts ...RecoveryOption) (ethcommon.Address, error) {
+	pub, err := RecoverPublicKeyEx(data, sig, opts...)
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// RecoverPublicKeyEx is an extension to RecoverPublicKey that supports the same signature formats as RecoveryAddressEx.
+func RecoverPublicKeyEx(data []byte, sig []byte, opts ...RecoveryOption) (*ecdsa.PublicKey, error) {
+	sig, _, err := normalizeSignatureEx(sig, newRecoveryOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return RecoverPublicKey(data, sig, opts...)
+}
+
+// normalizeSignatureEx converts the signature formats accepted by the Ex functions into the 65-byte form with V 27 or 28,
+// the returned method tells which of the formats the signature was in.
+func normalizeSignatureEx(sig []byte, options *recoveryOptions) ([]byte, VerificationMethod, error) {
+	method := MethodECDSA
 	sig = CopyBytes(sig)
+	if len(sig) == CompactSignatureLength {
+		if options.strictLowS {
+			return nil, method, fmt.Errorf("%w: compact signature", ErrNonCanonicalSignature)
+		}
+		expanded, err := ExpandCompactSignature(sig)
+		if err != nil {
+			return nil, method, err
+		}
+		sig, method = expanded, MethodECDSACompact
+	}
+	if options.eip155 {
+		decoded, chainID, err := decodeEIP155Signature(sig, options.chainID)
+		if err != nil {
+			return nil, method, err
+		}
+		if chainID != nil {
+			if options.strictLowS && options.chainID == nil {
+				// without an expected chain id every chain gives the signature another encoding
+				return nil, method, fmt.Errorf("%w: EIP-155 v without an expected chain id", ErrNonCanonicalSignature)
+			}
+			method = MethodECDSAEIP155
+		}
+		sig = decoded
+	}
 	if len(sig) != crypto.SignatureLength {
-		return ethcommon.Address{}, fmt.Errorf("signature must be %d bytes long", crypto.SignatureLength)
+		return nil, method, fmt.Errorf("%w: signature must be %d bytes long", ErrInvalidSignatureLength, crypto.SignatureLength)
 	}
 	// comment(storyicon): fix ledger wallet
 	// https://ethereum.stackexchange.com/questions/103307/cannot-verifiy-a-signature-produced-by-ledger-in-solidity-using-ecrecover
 	if sig[crypto.RecoveryIDOffset] == 0 || sig[crypto.RecoveryIDOffset] == 1 {
+		if options.strictLowS {
+			return nil, method, fmt.Errorf("%w: ledger v of %d", ErrNonCanonicalSignature, sig[crypto.RecoveryIDOffset])
+		}
 		sig[crypto.RecoveryIDOffset] += 27
+		method = MethodECDSALedger
 	}
-	return RecoveryAddress(data, sig)
+	return sig, method, nil
+}
+
+// EcRecoverPublicKeyEx is like EcRecoverEx but returns the public key of the signer,
+// which is useful when the caller needs more than the address, such as encrypting data to the signer with ECIES.
+func EcRecoverPublicKeyEx(data []byte, sig []byte, opts ...RecoveryOption) (*ecdsa.PublicKey, error) {
+	return RecoverPublicKeyEx(accounts.TextHash(data), sig, opts...)
+}
+
+// RecoveryAddressParsedEx is like RecoveryAddressEx but accepts a parsed Signature
+func RecoveryAddressParsedEx(data []byte, sig Signature, opts ...RecoveryOption) (ethcommon.Address, error) {
+	return RecoveryAddressEx(data, sig.Bytes(), opts...)
+}
+
+// RecoveryAddressEIP155 is like RecoveryAddressEx with WithEIP155 enabled, it additionally returns the chain id
+// decoded from an EIP-155 style v value (chainId*2+35 or chainId*2+36). The returned chain id is nil if v is not EIP-155 encoded.
+// If expectedChainID is not nil, a signature bound to another chain is rejected with ErrChainIDMismatch.
+func RecoveryAddressEIP155(data []byte, sig []byte, expectedChainID *big.Int, opts ...RecoveryOption) (ethcommon.Address, *big.Int, error) {
+	sig, chainID, err := decodeEIP155Signature(CopyBytes(sig), expectedChainID)
+	if err != nil {
+		return ethcommon.Address{}, nil, err
+	}
+	address, err := RecoveryAddressEx(data, sig, opts...)
+	if err != nil {
+		return ethcommon.Address{}, nil, err
+	}
+	return address, chainID, nil
+}
+
+// DecodeEIP155V splits an EIP-155 style v value (chainId*2+35 or chainId*2+36) into the chain id and the recovery id (0 or 1)
+func DecodeEIP155V(v *big.Int) (*big.Int, byte, error) {
+	if v == nil || v.Cmp(big.NewInt(35)) < 0 {
+		return nil, 0, fmt.Errorf("%w (invalid EIP-155 v value %v)", ErrInvalidRecoveryID, v)
+	}
+	x := new(big.Int).Sub(v, big.NewInt(35))
+	recoveryID := byte(x.Bit(0))
+	return x.Rsh(x, 1), recoveryID, nil
+}
+
+// maxEIP155VLength is the maximum number of bytes of an EIP-155 style v value, which holds a 64-bit chain id
+const maxEIP155VLength = 8
+
+// decodeEIP155Signature converts r || s || v, where v is a big-endian EIP-155 style value of one to maxEIP155VLength
+// bytes, into the 65-byte form with V 27 or 28. Signatures with a plain V are returned unchanged with a nil chain id.
+// v must be minimally encoded, so that a signature has a single byte encoding for a chain.
+func decodeEIP155Signature(sig []byte, expectedChainID *big.Int) ([]byte, *big.Int, error) {
+	if len(sig) < crypto.SignatureLength {
+		return sig, nil, nil
+	}
+	rawV := sig[crypto.RecoveryIDOffset:]
+	if len(rawV) > maxEIP155VLength {
+		return nil, nil, fmt.Errorf("%w: v must be at most %d bytes long", ErrInvalidSignatureLength, maxEIP155VLength)
+	}
+	if len(rawV) > 1 && rawV[0] == 0 {
+		return nil, nil, fmt.Errorf("%w (v has leading zero bytes)", ErrInvalidRecoveryID)
+	}
+	v := new(big.Int).SetBytes(rawV)
+	if len(sig) == crypto.SignatureLength && v.Cmp(big.NewInt(35)) < 0 {
+		return sig, nil, nil
+	}
+	chainID, recoveryID, err := DecodeEIP155V(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	if expectedChainID != nil && expectedChainID.Cmp(chainID) != 0 {
+		return nil, nil, fmt.Errorf("%w: expected %v, got %v", ErrChainIDMismatch, expectedChainID, chainID)
+	}
+	decoded := make([]byte, crypto.SignatureLength)
+	copy(decoded, sig[:crypto.RecoveryIDOffset])
+	decoded[crypto.RecoveryIDOffset] = 27 + recoveryID
+	return decoded, chainID, nil
 }
 
 // RecoveryAddress returns the address for the account that was used to create the signature, this function is almost a fork of EcRecover
 // However, EcRecover in go-ethereum will automatically perform accounts.TextHash for data in EcRecover,
 // which makes EIP712 unable to reuse this function
 // This design makes the function lose versatility, so this behavior is changed here
-func RecoveryAddress(data []byte, sig []byte) (ethcommon.Address, error) {
+//
+// By default any s value is accepted, pass WithStrictLowS to reject the malleable high-s form of a signature.
+func RecoveryAddress(data []byte, sig []byte, opts ...RecoveryOption) (ethcommon.Address, error) {
+	pub, err := RecoverPublicKey(data, sig, opts...)
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// RecoverPublicKey returns the secp256k1 public key that was used to create the signature,
+// it accepts the same signatures as RecoveryAddress.
+// Use crypto.FromECDSAPub and crypto.CompressPubkey to get its uncompressed and compressed encodings.
+func RecoverPublicKey(data []byte, sig []byte, opts ...RecoveryOption) (*ecdsa.PublicKey, error) {
+	options := newRecoveryOptions(opts)
 	sig = CopyBytes(sig)
 	if len(sig) != crypto.SignatureLength {
-		return ethcommon.Address{}, fmt.Errorf("signature must be %d bytes long", crypto.SignatureLength)
+		return nil, fmt.Errorf("%w: signature must be %d bytes long", ErrInvalidSignatureLength, crypto.SignatureLength)
 	}
 	if sig[crypto.RecoveryIDOffset] != 27 && sig[crypto.RecoveryIDOffset] != 28 {
-		return ethcommon.Address{}, fmt.Errorf("invalid Ethereum signature (V is not 27 or 28)")
+		return nil, fmt.Errorf("%w (V is not 27 or 28)", ErrInvalidRecoveryID)
+	}
+	if options.strictLowS && !IsLowS(sig) {
+		return nil, ErrHighS
 	}
 	sig[crypto.RecoveryIDOffset] -= 27 // Transform yellow paper V from 27/28 to 0/1
 
-	rpk, err := crypto.SigToPub(data, sig)
-	if err != nil {
-		return ethcommon.Address{}, err
-	}
-	return crypto.PubkeyToAddress(*rpk), nil
+	return crypto.SigToPub(data, sig)
+}
+
+// RecoveryAddressParsed is like RecoveryAddress but accepts a parsed Signature
+func RecoveryAddressParsed(data []byte, sig Signature, opts ...RecoveryOption) (ethcommon.Address, error) {
+	return RecoveryAddress(data, sig.Bytes(), opts...)
 }
 
 // EcRecover returns the address for the account that was used to create the signature,
@@ -102,6 +289,45 @@ func RecoveryAddress(data []byte, sig []byte) (ethcommon.Address, error) {
 // the V value must be 27 or 28 for legacy reasons.
 //
 // https://github.com/ethereum/go-ethereum/wiki/Management-APIs#personal_ecRecover
-func EcRecover(data []byte, sig []byte) (ethcommon.Address, error) {
-	return RecoveryAddress(accounts.TextHash(data), sig)
+func EcRecover(data []byte, sig []byte, opts ...RecoveryOption) (ethcommon.Address, error) {
+	return RecoveryAddress(accounts.TextHash(data), sig, opts...)
+}
+
+// EcRecoverParsed is like EcRecover but accepts a parsed Signature
+func EcRecoverParsed(data []byte, sig Signature, opts ...RecoveryOption) (ethcommon.Address, error) {
+	return EcRecover(data, sig.Bytes(), opts...)
+}
+
+// IsLowS reports whether the s value of the 65-byte signature is in the lower half of the curve order,
+// which is the only form accepted by ethereum since EIP-2.
+func IsLowS(sig []byte) bool {
+	if len(sig) != crypto.SignatureLength {
+		return false
+	}
+	return new(big.Int).SetBytes(sig[32:64]).Cmp(secp256k1HalfN) <= 0
+}
+
+// NormalizeSignature converts a high-s signature into its canonical low-s twin, that is
+// s' = secp256k1n - s with the recovery id flipped, both forms recover to the same address.
+// A signature that is already canonical is returned as a copy, the encoding of V (0/1 or 27/28) is kept.
+func NormalizeSignature(sig []byte) ([]byte, error) {
+	sig = CopyBytes(sig)
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("%w: signature must be %d bytes long", ErrInvalidSignatureLength, crypto.SignatureLength)
+	}
+	v := sig[crypto.RecoveryIDOffset]
+	if v != 0 && v != 1 && v != 27 && v != 28 {
+		return nil, fmt.Errorf("%w (V is not 0, 1, 27 or 28)", ErrInvalidRecoveryID)
+	}
+	if IsLowS(sig) {
+		return sig, nil
+	}
+	s := new(big.Int).Sub(secp256k1N, new(big.Int).SetBytes(sig[32:64]))
+	s.FillBytes(sig[32:64])
+	if v == 0 || v == 27 {
+		sig[crypto.RecoveryIDOffset]++
+	} else {
+		sig[crypto.RecoveryIDOffset]--
+	}
+	return sig, nil
 }
@@ -16,8 +16,11 @@ package sigverify
 
 import (
 	"fmt"
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -108,3 +111,155 @@ func TestEcRecoverEx(t *testing.T) {
 		})
 	}
 }
+
+func TestStrictLowS(t *testing.T) {
+	address := "0xb052C02346F80cF6ae4DF52c10FABD3e0aD24d81"
+	lowS := MustMustHexDecode(t, "0x0498c6564863c78e663848b963fde1ea1d860d5d882d2abdb707d1e9179ff80630a4a71705da534a562c08cb64a546c6132de26eb77a44f086832cbc1dbe01f71b")
+	assert.True(t, IsLowS(lowS))
+
+	// build the malleable twin (r, n-s, v^1) of the signature
+	highS := CopyBytes(lowS)
+	new(big.Int).Sub(secp256k1N, new(big.Int).SetBytes(lowS[32:64])).FillBytes(highS[32:64])
+	highS[64] = 28
+	assert.False(t, IsLowS(highS))
+
+	got, err := EcRecoverEx([]byte("hello"), highS)
+	assert.NoError(t, err)
+	assert.Equal(t, address, got.Hex())
+
+	_, err = EcRecoverEx([]byte("hello"), highS, WithStrictLowS())
+	assert.ErrorIs(t, err, ErrHighS)
+
+	got, err = EcRecoverEx([]byte("hello"), lowS, WithStrictLowS())
+	assert.NoError(t, err)
+	assert.Equal(t, address, got.Hex())
+
+	normalized, err := NormalizeSignature(highS)
+	assert.NoError(t, err)
+	assert.Equal(t, lowS, normalized)
+
+	normalized, err = NormalizeSignature(lowS)
+	assert.NoError(t, err)
+	assert.Equal(t, lowS, normalized)
+
+	compact, err := CompactSignature(lowS)
+	assert.NoError(t, err)
+	got, err = EcRecoverEx([]byte("hello"), compact)
+	assert.NoError(t, err)
+	assert.Equal(t, address, got.Hex())
+	_, err = EcRecoverEx([]byte("hello"), compact, WithStrictLowS())
+	assert.ErrorIs(t, err, ErrNonCanonicalSignature)
+
+	ledger := CopyBytes(lowS)
+	ledger[64] -= 27
+	got, err = EcRecoverEx([]byte("hello"), ledger)
+	assert.NoError(t, err)
+	assert.Equal(t, address, got.Hex())
+	_, err = EcRecoverEx([]byte("hello"), ledger, WithStrictLowS())
+	assert.ErrorIs(t, err, ErrNonCanonicalSignature)
+
+	// v = 1*2+35+0 binds the signature to chain 1, and v = 0x0125 to chain 129
+	eip155 := append(CopyBytes(lowS[:64]), 37)
+	wide := append(CopyBytes(lowS[:64]), 0x01, 0x25)
+	for _, sig := range [][]byte{eip155, wide} {
+		_, err = EcRecoverEx([]byte("hello"), sig, WithStrictLowS(), WithEIP155(nil))
+		assert.ErrorIs(t, err, ErrNonCanonicalSignature)
+	}
+	got, err = EcRecoverEx([]byte("hello"), eip155, WithStrictLowS(), WithEIP155(big.NewInt(1)))
+	assert.NoError(t, err)
+	assert.Equal(t, address, got.Hex())
+	_, err = EcRecoverEx([]byte("hello"), wide, WithStrictLowS(), WithEIP155(big.NewInt(1)))
+	assert.ErrorIs(t, err, ErrChainIDMismatch)
+	got, err = EcRecoverEx([]byte("hello"), lowS, WithStrictLowS(), WithEIP155(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, address, got.Hex())
+}
+
+func TestRecoveryAddressEIP155(t *testing.T) {
+	address := "0xb052C02346F80cF6ae4DF52c10FABD3e0aD24d81"
+	signature := MustMustHexDecode(t, "0x0498c6564863c78e663848b963fde1ea1d860d5d882d2abdb707d1e9179ff80630a4a71705da534a562c08cb64a546c6132de26eb77a44f086832cbc1dbe01f71b")
+	hash := accounts.TextHash([]byte("hello"))
+	withV := func(v *big.Int) []byte {
+		return append(CopyBytes(signature[:64]), v.Bytes()...)
+	}
+	tests := []struct {
+		name            string
+		sig             []byte
+		expectedChainID *big.Int
+		wantChainID     *big.Int
+		wantErr         error
+	}{
+		{
+			name: "plain v",
+			sig:  signature,
+		},
+		{
+			name:            "mainnet",
+			sig:             withV(big.NewInt(1*2 + 35)),
+			expectedChainID: big.NewInt(1),
+			wantChainID:     big.NewInt(1),
+		},
+		{
+			name:        "polygon with multi-byte v",
+			sig:         withV(big.NewInt(137*2 + 35)),
+			wantChainID: big.NewInt(137),
+		},
+		{
+			name:            "chain id mismatch",
+			sig:             withV(big.NewInt(137*2 + 35)),
+			expectedChainID: big.NewInt(1),
+			wantErr:         ErrChainIDMismatch,
+		},
+		{
+			name:            "zero padded v",
+			sig:             append(CopyBytes(signature[:64]), 0x00, 0x00, 0x25),
+			expectedChainID: big.NewInt(1),
+			wantErr:         ErrInvalidRecoveryID,
+		},
+		{
+			name:    "junk suffix",
+			sig:     append(CopyBytes(signature[:64]), 0x25, 0xde, 0xad, 0xbe, 0xef, 0xde, 0xad, 0xbe, 0xef),
+			wantErr: ErrInvalidSignatureLength,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr != nil {
+				_, err := RecoveryAddressEx(hash, tt.sig, WithEIP155(tt.expectedChainID))
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			got, chainID, err := RecoveryAddressEIP155(hash, tt.sig, tt.expectedChainID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, address, got.Hex())
+			assert.Equal(t, tt.wantChainID, chainID)
+
+			got, err = RecoveryAddressEx(hash, tt.sig, WithEIP155(tt.expectedChainID))
+			assert.NoError(t, err)
+			assert.Equal(t, address, got.Hex())
+		})
+	}
+
+	_, err := RecoveryAddressEx(hash, withV(big.NewInt(37)))
+	assert.Error(t, err, "EIP-155 v values must be opted in")
+}
+
+func TestEcRecoverPublicKeyEx(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	data := []byte("login")
+	// crypto.Sign returns V as 0/1, which is the same as the ledger format
+	sig, err := crypto.Sign(accounts.TextHash(data), key)
+	assert.NoError(t, err)
+
+	pub, err := EcRecoverPublicKeyEx(data, sig)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.FromECDSAPub(&key.PublicKey), crypto.FromECDSAPub(pub))
+	assert.Equal(t, crypto.CompressPubkey(&key.PublicKey), crypto.CompressPubkey(pub))
+
+	_, err = RecoverPublicKey(accounts.TextHash(data), sig)
+	assert.Error(t, err, "RecoverPublicKey requires V to be 27 or 28")
+}
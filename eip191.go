@@ -0,0 +1,195 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// EIP191Version is the version byte of EIP-191 signed data, which follows the 0x19 prefix
+type EIP191Version byte
+
+const (
+	// EIP191VersionIntendedValidator is 0x19 0x00 <validator address> <data>
+	EIP191VersionIntendedValidator EIP191Version = 0x00
+	// EIP191VersionStructuredData is 0x19 0x01 <domain separator> <hash of struct message>, defined by EIP-712
+	EIP191VersionStructuredData EIP191Version = 0x01
+	// EIP191VersionPersonalSign is 0x19 "Ethereum Signed Message:\n" <length of message> <message>
+	EIP191VersionPersonalSign EIP191Version = 0x45
+)
+
+// eip191Prefix is the first byte of EIP-191 signed data, which is not a valid RLP encoded transaction
+const eip191Prefix byte = 0x19
+
+// ErrUnsupportedEIP191Version is returned when the version byte of EIP-191 signed data is unknown
+var ErrUnsupportedEIP191Version = errors.New("unsupported EIP-191 version")
+
+// IntendedValidatorData returns the EIP-191 version 0x00 encoding 0x19 0x00 <validator address> <data>
+func IntendedValidatorData(validator ethcommon.Address, data []byte) []byte {
+	encoded := make([]byte, 0, 2+ethcommon.AddressLength+len(data))
+	encoded = append(encoded, eip191Prefix, byte(EIP191VersionIntendedValidator))
+	encoded = append(encoded, validator.Bytes()...)
+	return append(encoded, data...)
+}
+
+// HashIntendedValidatorData is used to calculate the hash of EIP-191 version 0x00 signed data,
+// hash = keccak256(0x19 0x00 <validator address> <data>), the validator is usually the contract that checks the signature
+func HashIntendedValidatorData(validator ethcommon.Address, data []byte) []byte {
+	return crypto.Keccak256(IntendedValidatorData(validator, data))
+}
+
+// RecoveryIntendedValidatorAddressEx is used to recover the signer address of EIP-191 version 0x00 signed data
+func RecoveryIntendedValidatorAddressEx(validator ethcommon.Address, data []byte, signature []byte, opts ...RecoveryOption) (ethcommon.Address, error) {
+	return RecoveryAddressEx(HashIntendedValidatorData(validator, data), signature, opts...)
+}
+
+// VerifyIntendedValidatorSignatureEx is used to verify the signer address of EIP-191 version 0x00 signed data
+func VerifyIntendedValidatorSignatureEx(address ethcommon.Address, validator ethcommon.Address, data []byte, signature []byte, opts ...RecoveryOption) (bool, error) {
+	recoveredAddress, err := RecoveryIntendedValidatorAddressEx(validator, data, signature, opts...)
+	if err != nil {
+		return false, err
+	}
+	return recoveredAddress == address, nil
+}
+
+// VerifyIntendedValidatorHexSignatureEx is like VerifyIntendedValidatorSignatureEx but accepts a hex encoded signature
+func VerifyIntendedValidatorHexSignatureEx(address ethcommon.Address, validator ethcommon.Address, data []byte, signature string, opts ...RecoveryOption) (bool, error) {
+	sig, err := HexDecode(signature)
+	if err != nil {
+		return false, err
+	}
+	return VerifyIntendedValidatorSignatureEx(address, validator, data, sig, opts...)
+}
+
+// VerifyIntendedValidatorSignature is the counterpart of VerifySignatureEx for EIP-191 version 0x00 signed data,
+// it tries the elliptic curve verification first and falls back to ERC1271 with the hash of the signed data.
+func VerifyIntendedValidatorSignature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, validator ethcommon.Address, data []byte, signature []byte, opts ...VerifyOption) (bool, error) {
+	result, err := VerifyIntendedValidatorSignatureDetailed(ctx, caller, address, validator, data, signature, opts...)
+	return result.Valid, err
+}
+
+// VerifyIntendedValidatorSignatureDetailed is like VerifyIntendedValidatorSignature, but returns a VerificationResult
+// that explains how the signature was verified.
+func VerifyIntendedValidatorSignatureDetailed(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, validator ethcommon.Address, data []byte, signature []byte, opts ...VerifyOption) (*VerificationResult, error) {
+	return VerifyHashSignatureExDetailed(ctx, caller, address, ethcommon.BytesToHash(HashIntendedValidatorData(validator, data)), signature, opts...)
+}
+
+// VerifyIntendedValidatorHexSignature is like VerifyIntendedValidatorSignature but accepts a hex encoded signature
+func VerifyIntendedValidatorHexSignature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, validator ethcommon.Address, data []byte, signature string, opts ...VerifyOption) (bool, error) {
+	sig, err := HexDecode(signature)
+	if err != nil {
+		return false, err
+	}
+	return VerifyIntendedValidatorSignature(ctx, caller, address, validator, data, sig, opts...)
+}
+
+// HashEIP191Data is used to calculate the hash of pre-encoded EIP-191 signed data, which is keccak256(encoded).
+// The scheme is picked from the version byte and the layout of encoded is checked against it:
+// version 0x00 needs a 20-byte validator address, version 0x01 needs exactly a 32-byte domain separator and
+// a 32-byte struct hash, and version 0x45 needs "Ethereum Signed Message:\n" followed by the decimal length
+// of the message that follows. Other versions are rejected with ErrUnsupportedEIP191Version.
+func HashEIP191Data(encoded []byte) ([]byte, EIP191Version, error) {
+	if len(encoded) < 2 || encoded[0] != eip191Prefix {
+		return nil, 0, errors.New("EIP-191 signed data must start with 0x19 and a version byte")
+	}
+	version := EIP191Version(encoded[1])
+	body := encoded[2:]
+	switch version {
+	case EIP191VersionIntendedValidator:
+		if len(body) < ethcommon.AddressLength {
+			return nil, version, fmt.Errorf("EIP-191 version 0x00 data has %d bytes, the validator address needs %d", len(body), ethcommon.AddressLength)
+		}
+	case EIP191VersionStructuredData:
+		if len(body) != 64 {
+			return nil, version, fmt.Errorf("EIP-191 version 0x01 data has %d bytes, expected 64", len(body))
+		}
+	case EIP191VersionPersonalSign:
+		if _, ok := personalSignMessage(body); !ok {
+			return nil, version, errors.New("EIP-191 version 0x45 data is not \"Ethereum Signed Message:\\n\" followed by the length of the message")
+		}
+	default:
+		return nil, version, fmt.Errorf("%w 0x%02x", ErrUnsupportedEIP191Version, byte(version))
+	}
+	return crypto.Keccak256(encoded), version, nil
+}
+
+// personalSignMessage returns the message of body, which is "thereum Signed Message:\n" <length of message> <message>,
+// the "E" being the version byte 0x45
+func personalSignMessage(body []byte) ([]byte, bool) {
+	prefix := []byte("thereum Signed Message:\n")
+	if !bytes.HasPrefix(body, prefix) {
+		return nil, false
+	}
+	rest := body[len(prefix):]
+	// the length and a message starting with digits can not be told apart, so any split that adds up is accepted
+	for i := 1; i <= len(rest); i++ {
+		length, err := strconv.ParseUint(string(rest[:i]), 10, 64)
+		if err != nil || (i > 1 && rest[0] == '0') {
+			break
+		}
+		if length == uint64(len(rest)-i) {
+			return rest[i:], true
+		}
+	}
+	return nil, false
+}
+
+// RecoveryEIP191AddressEx is used to recover the signer address of pre-encoded EIP-191 signed data of any version
+func RecoveryEIP191AddressEx(encoded []byte, signature []byte, opts ...RecoveryOption) (ethcommon.Address, error) {
+	hash, _, err := HashEIP191Data(encoded)
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+	return RecoveryAddressEx(hash, signature, opts...)
+}
+
+// VerifyEIP191Signature verifies a signature over pre-encoded EIP-191 signed data, the scheme is picked from
+// the version byte as HashEIP191Data does. It tries the elliptic curve verification first and falls back to ERC1271.
+func VerifyEIP191Signature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, encoded []byte, signature []byte, opts ...VerifyOption) (bool, error) {
+	result, err := VerifyEIP191SignatureDetailed(ctx, caller, address, encoded, signature, opts...)
+	return result.Valid, err
+}
+
+// VerifyEIP191SignatureDetailed is like VerifyEIP191Signature, but returns a VerificationResult
+// that explains how the signature was verified.
+// For version 0x45 the legacy isValidSignature(bytes,bytes) of ERC1271 receives the message, like VerifySignatureEx.
+func VerifyEIP191SignatureDetailed(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, encoded []byte, signature []byte, opts ...VerifyOption) (*VerificationResult, error) {
+	hash, version, err := HashEIP191Data(encoded)
+	if err != nil {
+		return &VerificationResult{Address: address}, err
+	}
+	digest := signedDigest{hash: ethcommon.BytesToHash(hash)}
+	if version == EIP191VersionPersonalSign {
+		digest.message, _ = personalSignMessage(encoded[2:])
+	}
+	return verifyHashSignature(ctx, caller, address, digest, signature, newVerifyOptions(opts))
+}
+
+// VerifyEIP191HexSignature is like VerifyEIP191Signature but accepts a hex encoded signature
+func VerifyEIP191HexSignature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, encoded []byte, signature string, opts ...VerifyOption) (bool, error) {
+	sig, err := HexDecode(signature)
+	if err != nil {
+		return false, err
+	}
+	return VerifyEIP191Signature(ctx, caller, address, encoded, sig, opts...)
+}
@@ -0,0 +1,135 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashIntendedValidatorData(t *testing.T) {
+	validator := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	data := []byte("execute")
+	want := crypto.Keccak256(append(append([]byte{0x19, 0x00}, validator.Bytes()...), data...))
+	assert.Equal(t, want, HashIntendedValidatorData(validator, data))
+
+	hash, version, err := HashEIP191Data(IntendedValidatorData(validator, data))
+	assert.NoError(t, err)
+	assert.Equal(t, EIP191VersionIntendedValidator, version)
+	assert.Equal(t, want, hash)
+}
+
+func TestVerifyIntendedValidatorSignature(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	ctx := context.Background()
+	validator := common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	data := []byte("execute")
+	signature := wallets.signHash(t, HashIntendedValidatorData(validator, data))
+
+	valid, err := VerifyIntendedValidatorSignatureEx(wallets.eoa, validator, data, signature)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	valid, err = VerifyIntendedValidatorHexSignatureEx(wallets.eoa, common.Address{}, data, hexutil.Encode(signature))
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	result, err := VerifyIntendedValidatorSignatureDetailed(ctx, wallets.backend, wallets.eoa, validator, data, signature)
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, MethodECDSA, result.Method)
+
+	result, err = VerifyIntendedValidatorSignatureDetailed(ctx, wallets.backend, wallets.owner, validator, data, signature)
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, MethodERC1271, result.Method)
+
+	valid, err = VerifyIntendedValidatorHexSignature(ctx, wallets.backend, wallets.owner, wallets.owner, data, hexutil.Encode(signature))
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestHashEIP191Data(t *testing.T) {
+	data := ambireTypedData()
+	domainSeparator, err := data.HashStruct("EIP712Domain", data.Domain.Map())
+	assert.NoError(t, err)
+	structHash, typedDataHash, err := HashTypedData(data)
+	assert.NoError(t, err)
+	structured := append(append([]byte{0x19, 0x01}, domainSeparator...), structHash...)
+
+	personal := []byte("\x19Ethereum Signed Message:\n5hello")
+	digits := []byte("\x19Ethereum Signed Message:\n212")
+
+	tests := []struct {
+		name        string
+		encoded     []byte
+		wantHash    []byte
+		wantVersion EIP191Version
+		wantErr     error
+	}{
+		{name: "structured data", encoded: structured, wantHash: typedDataHash, wantVersion: EIP191VersionStructuredData},
+		{name: "personal sign", encoded: personal, wantHash: accounts.TextHash([]byte("hello")), wantVersion: EIP191VersionPersonalSign},
+		{name: "personal sign/message of digits", encoded: digits, wantHash: accounts.TextHash([]byte("12")), wantVersion: EIP191VersionPersonalSign},
+		{name: "personal sign/wrong length", encoded: []byte("\x19Ethereum Signed Message:\n6hello"), wantVersion: EIP191VersionPersonalSign},
+		{name: "personal sign/no length", encoded: []byte("\x19Ethereum Signed Message:\nhello"), wantVersion: EIP191VersionPersonalSign},
+		{name: "structured data/short", encoded: structured[:65], wantVersion: EIP191VersionStructuredData},
+		{name: "intended validator/short", encoded: []byte{0x19, 0x00, 0x01}, wantVersion: EIP191VersionIntendedValidator},
+		{name: "unsupported version", encoded: []byte{0x19, 0x02, 0x01}, wantVersion: 0x02, wantErr: ErrUnsupportedEIP191Version},
+		{name: "no prefix", encoded: []byte("hello")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, version, err := HashEIP191Data(tt.encoded)
+			assert.Equal(t, tt.wantVersion, version)
+			if tt.wantHash == nil {
+				assert.Error(t, err)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHash, hash)
+		})
+	}
+}
+
+func TestVerifyEIP191Signature(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	ctx := context.Background()
+	encoded := []byte("\x19Ethereum Signed Message:\n5hello")
+	signature := wallets.sign(t, []byte("hello"))
+
+	recovered, err := RecoveryEIP191AddressEx(encoded, signature)
+	assert.NoError(t, err)
+	assert.Equal(t, wallets.eoa, recovered)
+
+	valid, err := VerifyEIP191Signature(ctx, wallets.backend, wallets.eoa, encoded, signature)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = VerifyEIP191HexSignature(ctx, wallets.backend, wallets.owner, encoded, hexutil.Encode(signature))
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = VerifyEIP191Signature(ctx, wallets.backend, wallets.owner, []byte{0x19, 0x03}, signature)
+	assert.ErrorIs(t, err, ErrUnsupportedEIP191Version)
+	assert.False(t, valid)
+}
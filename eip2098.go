@@ -0,0 +1,53 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// CompactSignatureLength is the length of the EIP-2098 compact signature: r || yParityAndS
+const CompactSignatureLength = 64
+
+// ExpandCompactSignature converts an EIP-2098 compact signature into the 65-byte r || s || v form,
+// where the highest bit of yParityAndS is the y parity and the remaining bits are s.
+// https://eips.ethereum.org/EIPS/eip-2098
+func ExpandCompactSignature(compact []byte) ([]byte, error) {
+	if len(compact) != CompactSignatureLength {
+		return nil, fmt.Errorf("%w: compact signature must be %d bytes long", ErrInvalidSignatureLength, CompactSignatureLength)
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig, compact)
+	sig[32] &= 0x7f
+	sig[crypto.RecoveryIDOffset] = 27 + compact[32]>>7
+	return sig, nil
+}
+
+// CompactSignature converts a 65-byte r || s || v signature into the EIP-2098 compact form.
+// V may be 0/1 or 27/28, since the compact form can only carry a low s value,
+// a high-s signature is normalized with NormalizeSignature first.
+func CompactSignature(sig []byte) ([]byte, error) {
+	sig, err := NormalizeSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	compact := sig[:CompactSignatureLength]
+	if v := sig[crypto.RecoveryIDOffset]; v == 1 || v == 28 {
+		compact[32] |= 0x80
+	}
+	return compact, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// test vectors come from https://eips.ethereum.org/EIPS/eip-2098
+func TestCompactSignature(t *testing.T) {
+	address := common.HexToAddress("0x2e988A386a799F506693793c6A5AF6B54dfAaBfB")
+	tests := []struct {
+		name      string
+		message   string
+		signature string
+		compact   string
+	}{
+		{
+			name:      "y parity 0",
+			message:   "Hello World",
+			signature: "0x68a020a209d3d56c46f38cc50a33f704f4a9a10a59377f8dd762ac66910e9b907e865ad05c4035ab5792787d4a0297a43617ae897930a6fe4d822b8faea520641b",
+			compact:   "0x68a020a209d3d56c46f38cc50a33f704f4a9a10a59377f8dd762ac66910e9b907e865ad05c4035ab5792787d4a0297a43617ae897930a6fe4d822b8faea52064",
+		},
+		{
+			name:      "y parity 1",
+			message:   "It's a small(er) world",
+			signature: "0x9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76139c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f5507931c",
+			compact:   "0x9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76939c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f550793",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signature := MustMustHexDecode(t, tt.signature)
+			compact := MustMustHexDecode(t, tt.compact)
+
+			got, err := CompactSignature(signature)
+			assert.NoError(t, err)
+			assert.Equal(t, compact, got)
+
+			got, err = ExpandCompactSignature(compact)
+			assert.NoError(t, err)
+			assert.Equal(t, signature, got)
+
+			valid, err := VerifyEllipticCurveSignatureEx(address, []byte(tt.message), compact)
+			assert.NoError(t, err)
+			assert.True(t, valid)
+
+			valid, err = VerifyEllipticCurveHexSignatureEx(address, []byte(tt.message), tt.compact)
+			assert.NoError(t, err)
+			assert.True(t, valid)
+		})
+	}
+}
@@ -15,6 +15,7 @@
 package sigverify
 
 import (
+	"crypto/ecdsa"
 	"fmt"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
@@ -39,32 +40,64 @@ func HashTypedData(data apitypes.TypedData) ([]byte, []byte, error) {
 }
 
 // RecoveryTypedDataAddressEx is used to recover the signer address of the TypedData signature
-func RecoveryTypedDataAddressEx(data apitypes.TypedData, signature []byte) (ethcommon.Address, error) {
+func RecoveryTypedDataAddressEx(data apitypes.TypedData, signature []byte, opts ...RecoveryOption) (ethcommon.Address, error) {
 	_, dataHash, err := HashTypedData(data)
 	if err != nil {
 		return ethcommon.Address{}, err
 	}
-	return RecoveryAddressEx(dataHash, signature)
+	return RecoveryAddressEx(dataHash, signature, opts...)
 }
 
-// VerifyTypedDataSignatureEx is used to verify the signer address of the TypedData signature
-func VerifyTypedDataSignatureEx(address ethcommon.Address, data apitypes.TypedData, signature []byte) (bool, error) {
-	recoveredAddress, err := RecoveryTypedDataAddressEx(data, signature)
+// RecoverTypedDataPublicKeyEx is used to recover the signer public key of the TypedData signature
+func RecoverTypedDataPublicKeyEx(data apitypes.TypedData, signature []byte, opts ...RecoveryOption) (*ecdsa.PublicKey, error) {
+	_, dataHash, err := HashTypedData(data)
+	if err != nil {
+		return nil, err
+	}
+	return RecoverPublicKeyEx(dataHash, signature, opts...)
+}
+
+// RecoveryTypedDataAddressParsedEx is like RecoveryTypedDataAddressEx but accepts a parsed Signature
+func RecoveryTypedDataAddressParsedEx(data apitypes.TypedData, signature Signature, opts ...RecoveryOption) (ethcommon.Address, error) {
+	return RecoveryTypedDataAddressEx(data, signature.Bytes(), opts...)
+}
+
+// VerifyTypedDataSignatureEx is used to verify the signer address of the TypedData signature.
+// It only does the elliptic curve verification, use VerifyTypedDataSignatureWithFallback to fall back to ERC1271
+// for contract accounts.
+func VerifyTypedDataSignatureEx(address ethcommon.Address, data apitypes.TypedData, signature []byte, opts ...RecoveryOption) (bool, error) {
+	recoveredAddress, err := RecoveryTypedDataAddressEx(data, signature, opts...)
 	if err != nil {
 		return false, err
 	}
 	return recoveredAddress == address, nil
 }
 
+// VerifyTypedDataSignatureExDetailed is like VerifyTypedDataSignatureEx,
+// but returns a VerificationResult that explains how the signature was verified.
+func VerifyTypedDataSignatureExDetailed(address ethcommon.Address, data apitypes.TypedData, signature []byte, opts ...RecoveryOption) (*VerificationResult, error) {
+	_, dataHash, err := HashTypedData(data)
+	if err != nil {
+		return &VerificationResult{Address: address}, err
+	}
+	result := newVerificationResult(address, dataHash)
+	return result, verifyEllipticCurveDigest(result, signature, opts)
+}
+
 // VerifyTypedDataHexSignatureEx is used to verify the signer address of the TypedData signature
-func VerifyTypedDataHexSignatureEx(address ethcommon.Address, data apitypes.TypedData, signature string) (bool, error) {
+func VerifyTypedDataHexSignatureEx(address ethcommon.Address, data apitypes.TypedData, signature string, opts ...RecoveryOption) (bool, error) {
 	sig, err := HexDecode(signature)
 	if err != nil {
 		return false, err
 	}
-	recoveredAddress, err := RecoveryTypedDataAddressEx(data, sig)
+	recoveredAddress, err := RecoveryTypedDataAddressEx(data, sig, opts...)
 	if err != nil {
 		return false, err
 	}
 	return recoveredAddress == address, nil
 }
+
+// VerifyTypedDataParsedSignatureEx is like VerifyTypedDataSignatureEx but accepts a parsed Signature
+func VerifyTypedDataParsedSignatureEx(address ethcommon.Address, data apitypes.TypedData, signature Signature, opts ...RecoveryOption) (bool, error) {
+	return VerifyTypedDataSignatureEx(address, data, signature.Bytes(), opts...)
+}
@@ -15,6 +15,7 @@
 package sigverify
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"testing"
@@ -25,8 +26,32 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestVerifyTypedDataHexSignatureEx(t *testing.T) {
+func ambireTypedData() apitypes.TypedData {
 	chainId := math.HexOrDecimal256(*big.NewInt(1))
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": []apitypes.Type{
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+			},
+			"RandomAmbireTypeStruct": []apitypes.Type{
+				{Name: "identity", Type: "address"},
+				{Name: "rewards", Type: "uint256"},
+			},
+		},
+		Domain: apitypes.TypedDataDomain{
+			Name:    "Ambire Typed test message",
+			ChainId: &chainId,
+		},
+		PrimaryType: "RandomAmbireTypeStruct",
+		Message: apitypes.TypedDataMessage{
+			"identity": "0x0000000000000000000000000000000000000000",
+			"rewards":  "0",
+		},
+	}
+}
+
+func TestVerifyTypedDataHexSignatureEx(t *testing.T) {
 	type args struct {
 		address   common.Address
 		data      apitypes.TypedData
@@ -41,28 +66,8 @@ func TestVerifyTypedDataHexSignatureEx(t *testing.T) {
 		{
 			name: "TypedData",
 			args: args{
-				address: common.HexToAddress("0xaC39b311DCEb2A4b2f5d8461c1cdaF756F4F7Ae9"),
-				data: apitypes.TypedData{
-					Types: apitypes.Types{
-						"EIP712Domain": []apitypes.Type{
-							{Name: "name", Type: "string"},
-							{Name: "chainId", Type: "uint256"},
-						},
-						"RandomAmbireTypeStruct": []apitypes.Type{
-							{Name: "identity", Type: "address"},
-							{Name: "rewards", Type: "uint256"},
-						},
-					},
-					Domain: apitypes.TypedDataDomain{
-						Name:    "Ambire Typed test message",
-						ChainId: &chainId,
-					},
-					PrimaryType: "RandomAmbireTypeStruct",
-					Message: apitypes.TypedDataMessage{
-						"identity": "0x0000000000000000000000000000000000000000",
-						"rewards":  "0",
-					},
-				},
+				address:   common.HexToAddress("0xaC39b311DCEb2A4b2f5d8461c1cdaF756F4F7Ae9"),
+				data:      ambireTypedData(),
 				signature: "0xee0d9f9e63fa7183bea2ca2e614cf539464a4c120c8dfc1d5ccc367f242a2c5939d7f59ec2ab413b8a9047de5de2f1e5e97da4eba2ef0d6a89136464f992dae11c",
 			},
 			want:    true,
@@ -79,3 +84,68 @@ func TestVerifyTypedDataHexSignatureEx(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyTypedDataSignatureWithFallback(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	data := ambireTypedData()
+	_, dataHash, err := HashTypedData(data)
+	assert.NoError(t, err)
+	ownerSignature := wallets.signHash(t, dataHash)
+	tests := []struct {
+		name       string
+		address    common.Address
+		signature  []byte
+		want       bool
+		wantMethod VerificationMethod
+		wantErr    error
+	}{
+		{
+			name:       "ecdsa",
+			address:    common.HexToAddress("0xaC39b311DCEb2A4b2f5d8461c1cdaF756F4F7Ae9"),
+			signature:  MustMustHexDecode(t, "0xee0d9f9e63fa7183bea2ca2e614cf539464a4c120c8dfc1d5ccc367f242a2c5939d7f59ec2ab413b8a9047de5de2f1e5e97da4eba2ef0d6a89136464f992dae11c"),
+			want:       true,
+			wantMethod: MethodECDSA,
+		},
+		{
+			name:       "erc1271",
+			address:    wallets.owner,
+			signature:  ownerSignature,
+			want:       true,
+			wantMethod: MethodERC1271,
+		},
+		{
+			name:      "erc1271/text signature",
+			address:   wallets.owner,
+			signature: wallets.sign(t, dataHash),
+		},
+		{
+			name:      "erc1271/reverting",
+			address:   wallets.reverting,
+			signature: ownerSignature,
+			wantErr:   ErrExecutionReverted,
+		},
+		{
+			name:      "eoa/signed by another key",
+			address:   wallets.eoa,
+			signature: MustMustHexDecode(t, "0xee0d9f9e63fa7183bea2ca2e614cf539464a4c120c8dfc1d5ccc367f242a2c5939d7f59ec2ab413b8a9047de5de2f1e5e97da4eba2ef0d6a89136464f992dae11c"),
+			wantErr:   ErrNoContractCode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := VerifyTypedDataSignatureWithFallbackDetailed(context.Background(), wallets.backend, tt.address, data, tt.signature)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, result.Valid)
+			assert.Equal(t, tt.wantMethod, result.Method)
+			assert.Equal(t, common.BytesToHash(dataHash), result.Digest)
+		})
+	}
+
+	valid, err := VerifyERC1271TypedDataSignature(context.Background(), wallets.backend, wallets.owner, data, ownerSignature)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
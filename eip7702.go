@@ -0,0 +1,93 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// SetCodeAuthorizationMagic is prepended to the RLP encoding of an EIP-7702 authorization before hashing
+const SetCodeAuthorizationMagic byte = 0x05
+
+// SetCodeAuthorization is an EIP-7702 authorization tuple (chain_id, address, nonce, y_parity, r, s),
+// by which the authority delegates the code of its EOA to Address.
+// The field order follows the RLP encoding of an element of the authorization_list of a set-code transaction.
+type SetCodeAuthorization struct {
+	// ChainID is the chain the authorization is valid on, 0 means any chain
+	ChainID *big.Int
+	Address ethcommon.Address
+	Nonce   uint64
+	YParity uint8
+	R       *big.Int
+	S       *big.Int
+}
+
+// SigningHash returns keccak256(0x05 || rlp([chain_id, address, nonce])), which is signed by the authority
+func (a *SetCodeAuthorization) SigningHash() ethcommon.Hash {
+	chainID := a.ChainID
+	if chainID == nil {
+		chainID = new(big.Int)
+	}
+	payload, _ := rlp.EncodeToBytes([]interface{}{chainID, a.Address, a.Nonce})
+	return crypto.Keccak256Hash([]byte{SetCodeAuthorizationMagic}, payload)
+}
+
+// Signature returns the 65-byte r || s || v signature of the authorization, v is 27 or 28
+func (a *SetCodeAuthorization) Signature() ([]byte, error) {
+	if a.YParity > 1 {
+		return nil, fmt.Errorf("%w (y_parity is not 0 or 1)", ErrInvalidRecoveryID)
+	}
+	if a.R == nil || a.S == nil || a.R.Sign() < 0 || a.S.Sign() < 0 || a.R.BitLen() > 256 || a.S.BitLen() > 256 {
+		return nil, errors.New("invalid authorization: r and s must be 256-bit unsigned integers")
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	a.R.FillBytes(sig[:32])
+	a.S.FillBytes(sig[32:64])
+	sig[crypto.RecoveryIDOffset] = a.YParity + 27
+	return sig, nil
+}
+
+// RecoverSetCodeAuthority recovers the authority that signed the authorization, following the checks of EIP-7702:
+// the chain id must be 0 or expectedChainID (ErrChainIDMismatch), the nonce must be less than 2^64-1,
+// y_parity must be 0 or 1 (ErrInvalidRecoveryID), and s must not be greater than secp256k1n/2 (ErrHighS).
+// If expectedChainID is nil, the chain id is not checked.
+// The nonce is not compared with the nonce of the authority, which needs the state of the chain.
+func RecoverSetCodeAuthority(auth *SetCodeAuthorization, expectedChainID *big.Int) (ethcommon.Address, error) {
+	if auth.ChainID != nil && auth.ChainID.Sign() != 0 && expectedChainID != nil && auth.ChainID.Cmp(expectedChainID) != 0 {
+		return ethcommon.Address{}, fmt.Errorf("%w: authorization is bound to chain %s, expected %s", ErrChainIDMismatch, auth.ChainID, expectedChainID)
+	}
+	if auth.Nonce == math.MaxUint64 {
+		return ethcommon.Address{}, errors.New("invalid authorization: nonce must be less than 2^64-1")
+	}
+	sig, err := auth.Signature()
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+	if !IsLowS(sig) {
+		return ethcommon.Address{}, ErrHighS
+	}
+	if !crypto.ValidateSignatureValues(auth.YParity, auth.R, auth.S, true) {
+		return ethcommon.Address{}, errors.New("invalid authorization: r or s is out of range")
+	}
+	hash := auth.SigningHash()
+	return RecoveryAddress(hash[:], sig, WithStrictLowS())
+}
@@ -0,0 +1,133 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// signSetCodeAuthorization signs auth in place with key
+func signSetCodeAuthorization(t *testing.T, auth *SetCodeAuthorization, key []byte) {
+	privateKey, err := crypto.ToECDSA(key)
+	assert.NoError(t, err)
+	hash := auth.SigningHash()
+	sig, err := crypto.Sign(hash[:], privateKey)
+	assert.NoError(t, err)
+	auth.R = new(big.Int).SetBytes(sig[:32])
+	auth.S = new(big.Int).SetBytes(sig[32:64])
+	auth.YParity = sig[crypto.RecoveryIDOffset]
+}
+
+func TestSetCodeAuthorizationSigningHash(t *testing.T) {
+	delegate := common.HexToAddress("0x63c0c19a282a1B52b07dD5a65b58948A07DAE32B")
+	auth := &SetCodeAuthorization{ChainID: big.NewInt(1), Address: delegate, Nonce: 0}
+	// 0x05 || rlp([1, delegate, 0])
+	payload := append([]byte{0x05, 0xd7, 0x01, 0x94}, delegate[:]...)
+	payload = append(payload, 0x80)
+	assert.Equal(t, crypto.Keccak256Hash(payload), auth.SigningHash())
+
+	// a nil chain id is encoded as 0
+	assert.Equal(t, (&SetCodeAuthorization{ChainID: big.NewInt(0), Address: delegate}).SigningHash(), (&SetCodeAuthorization{Address: delegate}).SigningHash())
+}
+
+func TestRecoverSetCodeAuthority(t *testing.T) {
+	key := crypto.Keccak256([]byte("authority"))
+	privateKey, err := crypto.ToECDSA(key)
+	assert.NoError(t, err)
+	authority := crypto.PubkeyToAddress(privateKey.PublicKey)
+	delegate := common.HexToAddress("0x63c0c19a282a1B52b07dD5a65b58948A07DAE32B")
+
+	signed := func(chainID int64, nonce uint64) *SetCodeAuthorization {
+		auth := &SetCodeAuthorization{ChainID: big.NewInt(chainID), Address: delegate, Nonce: nonce}
+		signSetCodeAuthorization(t, auth, key)
+		return auth
+	}
+	highS := signed(1, 7)
+	highS.S = new(big.Int).Sub(secp256k1N, highS.S)
+	highS.YParity ^= 1
+	badParity := signed(1, 7)
+	badParity.YParity = 2
+	zeroR := signed(1, 7)
+	zeroR.R = new(big.Int)
+
+	tests := []struct {
+		name            string
+		auth            *SetCodeAuthorization
+		expectedChainID *big.Int
+		wantErr         error
+		wantAnyErr      bool
+	}{
+		{
+			name:            "same chain",
+			auth:            signed(1, 7),
+			expectedChainID: big.NewInt(1),
+		},
+		{
+			name:            "any chain",
+			auth:            signed(0, 7),
+			expectedChainID: big.NewInt(1),
+		},
+		{
+			name: "chain not checked",
+			auth: signed(5, 7),
+		},
+		{
+			name:            "other chain",
+			auth:            signed(5, 7),
+			expectedChainID: big.NewInt(1),
+			wantErr:         ErrChainIDMismatch,
+		},
+		{
+			name:    "high s",
+			auth:    highS,
+			wantErr: ErrHighS,
+		},
+		{
+			name:    "y parity",
+			auth:    badParity,
+			wantErr: ErrInvalidRecoveryID,
+		},
+		{
+			name:       "max nonce",
+			auth:       signed(1, math.MaxUint64),
+			wantAnyErr: true,
+		},
+		{
+			name:       "zero r",
+			auth:       zeroR,
+			wantAnyErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RecoverSetCodeAuthority(tt.auth, tt.expectedChainID)
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantAnyErr:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, authority, got)
+			}
+		})
+	}
+}
@@ -16,11 +16,12 @@ package sigverify
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
-	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/storyicon/sigverify/contracts/erc1271"
 )
 
@@ -30,35 +31,177 @@ var magicValueERC1271 = [4]byte{
 	22, 38, 186, 126,
 }
 
+// magicValueERC1271Legacy is defined in the draft of ERC1271, which comes from:
+// bytes4(keccak256("isValidSignature(bytes,bytes)")
+var magicValueERC1271Legacy = [4]byte{
+	32, 193, 59, 11,
+}
+
 // GetERC1271Magic is used to get the magic value defined by ERC1271
 func GetERC1271Magic() [4]byte {
 	return magicValueERC1271
 }
 
+// GetERC1271LegacyMagic is used to get the magic value defined by the draft of ERC1271,
+// which is returned by the isValidSignature(bytes,bytes) of early smart contract wallets
+func GetERC1271LegacyMagic() [4]byte {
+	return magicValueERC1271Legacy
+}
+
 // VerifyERC1271HexSignature is a helper function.
 // look up VerifyERC1271 for more comments.
-func VerifyERC1271HexSignature(ctx context.Context, client *ethclient.Client, address ethcommon.Address, data []byte, signature string) (bool, error) {
+func VerifyERC1271HexSignature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, data []byte, signature string, opts ...VerifyOption) (bool, error) {
 	sig, err := HexDecode(signature)
 	if err != nil {
 		return false, err
 	}
-	return VerifyERC1271Signature(ctx, client, address, data, sig)
+	return VerifyERC1271Signature(ctx, caller, address, data, sig, opts...)
+}
+
+// VerifyERC1271ParsedSignature is like VerifyERC1271Signature but accepts a parsed Signature,
+// the contract receives the 65-byte r || s || v encoding of it.
+func VerifyERC1271ParsedSignature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, data []byte, signature Signature, opts ...VerifyOption) (bool, error) {
+	return VerifyERC1271Signature(ctx, caller, address, data, signature.Bytes(), opts...)
 }
 
 // VerifyERC1271Signature verifies signatures based on the ERC1271 standard
-// 1. When the given address is EOA, "no contract code at given address" will be thrown:
-// 2. When the given address is a contract but does not conform to the erc1271 specification, "execution reverted" will be thrown
-func VerifyERC1271Signature(ctx context.Context, client *ethclient.Client, address ethcommon.Address, data []byte, signature []byte) (bool, error) {
-	contract, err := erc1271.NewErc1271(address, client)
+// caller can be any backend that implements bind.ContractCaller, such as *ethclient.Client or the simulated backend.
+// The calls are performed on the latest state, look up WithBlockNumber, WithBlockHash and WithPendingState for other states.
+// Both isValidSignature(bytes32,bytes) and the legacy isValidSignature(bytes,bytes) are probed, look up WithERC1271Methods.
+// The former receives the hash of the personal message, the latter receives data itself, which it hashes on its own.
+// Signatures wrapped as defined by ERC6492 are verified even if the wallet is not deployed yet, look up WrapERC6492Signature.
+// 1. When the given address is EOA, ErrNoContractCode ("no contract code at given address") will be thrown:
+// 2. When the given address is a contract but does not conform to the erc1271 specification, a *RevertError
+// matching ErrExecutionReverted ("execution reverted") will be thrown, with the revert reason decoded if available
+func VerifyERC1271Signature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, data []byte, signature []byte, opts ...VerifyOption) (bool, error) {
+	result, err := VerifyERC1271SignatureDetailed(ctx, caller, address, data, signature, opts...)
+	return result.Valid, err
+}
+
+// VerifyERC1271SignatureDetailed is like VerifyERC1271Signature,
+// but returns a VerificationResult that explains how the signature was verified.
+func VerifyERC1271SignatureDetailed(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, data []byte, signature []byte, opts ...VerifyOption) (*VerificationResult, error) {
+	digest := textDigest(data)
+	result := newVerificationResult(address, digest.hash[:])
+	result.message = digest.message
+	return result, verifyERC1271Digest(ctx, caller, result, signature, newVerifyOptions(opts))
+}
+
+// VerifyERC1271Hash calls isValidSignature(hash, signature) on the given address without hashing anything itself,
+// so that signatures over permit digests, Safe transaction hashes or any custom hashing scheme can be verified.
+// The errors are the same as VerifyERC1271Signature.
+func VerifyERC1271Hash(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, hash [32]byte, signature []byte, opts ...VerifyOption) (bool, error) {
+	result, err := VerifyERC1271HashDetailed(ctx, caller, address, hash, signature, opts...)
+	return result.Valid, err
+}
+
+// VerifyERC1271HashDetailed is like VerifyERC1271Hash,
+// but returns a VerificationResult that explains how the signature was verified.
+func VerifyERC1271HashDetailed(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, hash [32]byte, signature []byte, opts ...VerifyOption) (*VerificationResult, error) {
+	result := newVerificationResult(address, hash[:])
+	return result, verifyERC1271Digest(ctx, caller, result, signature, newVerifyOptions(opts))
+}
+
+// VerifyERC1271TypedDataSignature verifies EIP-712 typed data signatures based on the ERC1271 standard,
+// isValidSignature is called with the hash returned by HashTypedData. The errors are the same as VerifyERC1271Signature.
+func VerifyERC1271TypedDataSignature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, data apitypes.TypedData, signature []byte, opts ...VerifyOption) (bool, error) {
+	result, err := VerifyERC1271TypedDataSignatureDetailed(ctx, caller, address, data, signature, opts...)
+	return result.Valid, err
+}
+
+// VerifyERC1271TypedDataHexSignature is a helper function.
+// look up VerifyERC1271TypedDataSignature for more comments.
+func VerifyERC1271TypedDataHexSignature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, data apitypes.TypedData, signature string, opts ...VerifyOption) (bool, error) {
+	sig, err := HexDecode(signature)
 	if err != nil {
 		return false, err
 	}
-	dataHash := ethcommon.BytesToHash(accounts.TextHash(data))
-	magic, err := contract.IsValidSignature(&bind.CallOpts{
-		Context: ctx,
-	}, dataHash, signature)
+	return VerifyERC1271TypedDataSignature(ctx, caller, address, data, sig, opts...)
+}
+
+// VerifyERC1271TypedDataSignatureDetailed is like VerifyERC1271TypedDataSignature,
+// but returns a VerificationResult that explains how the signature was verified.
+func VerifyERC1271TypedDataSignatureDetailed(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, data apitypes.TypedData, signature []byte, opts ...VerifyOption) (*VerificationResult, error) {
+	_, dataHash, err := HashTypedData(data)
 	if err != nil {
-		return false, err
+		return &VerificationResult{Address: address}, err
+	}
+	return VerifyERC1271HashDetailed(ctx, caller, address, ethcommon.BytesToHash(dataHash), signature, opts...)
+}
+
+// verifyERC1271Digest probes the isValidSignature variants of result.Address in the configured order.
+// The first variant that returns its magic value validates the signature. If none does, the returned error is nil
+// when at least one variant answered, otherwise it is the error of the first variant.
+// ERC6492 signatures are handed over to the deployless validator instead.
+// Every call is performed on the state of the block configured by options.
+func verifyERC1271Digest(ctx context.Context, caller bind.ContractCaller, result *VerificationResult, signature []byte, options *verifyOptions) error {
+	block := options.block
+	result.Block = &block
+	caller, err := pinContractCaller(caller, block)
+	if err != nil {
+		return err
+	}
+	if IsERC6492Signature(signature) {
+		return verifyERC6492Digest(ctx, caller, result, signature, options)
+	}
+	var (
+		firstErr error
+		answered bool
+	)
+	for _, method := range options.erc1271Methods {
+		magic, expected, err := callIsValidSignature(ctx, caller, result.Address, method, result.Digest, result.message, signature)
+		if err != nil {
+			err = wrapCallError(err, options.errorABIs...)
+			result.fail(method, err)
+			if errors.Is(err, ErrNoContractCode) {
+				return err
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if magic != expected {
+			result.fail(method, fmt.Errorf("%w: isValidSignature returned 0x%x", ErrInvalidMagicValue, magic))
+			answered = true
+			continue
+		}
+		result.succeed(method)
+		return nil
+	}
+	if answered {
+		return nil
+	}
+	return firstErr
+}
+
+// callIsValidSignature calls the isValidSignature variant of the method, and returns the value returned
+// by the contract together with the magic value expected by the variant.
+// The legacy variant receives message as _data, or the 32-byte digest when the message is not known.
+func callIsValidSignature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, method VerificationMethod, digest ethcommon.Hash, message []byte, signature []byte) ([4]byte, [4]byte, error) {
+	opts := &bind.CallOpts{
+		Context: ctx,
+	}
+	switch method {
+	case MethodERC1271:
+		contract, err := erc1271.NewErc1271Caller(address, caller)
+		if err != nil {
+			return [4]byte{}, GetERC1271Magic(), err
+		}
+		magic, err := contract.IsValidSignature(opts, digest, signature)
+		return magic, GetERC1271Magic(), err
+	case MethodERC1271Legacy:
+		contract, err := erc1271.NewErc1271LegacyCaller(address, caller)
+		if err != nil {
+			return [4]byte{}, GetERC1271LegacyMagic(), err
+		}
+		data := message
+		if data == nil {
+			data = digest[:]
+		}
+		magic, err := contract.IsValidSignature(opts, data, signature)
+		return magic, GetERC1271LegacyMagic(), err
+	default:
+		return [4]byte{}, [4]byte{}, fmt.Errorf("unsupported ERC1271 method %q", method)
 	}
-	return GetERC1271Magic() == magic, nil
 }
@@ -16,11 +16,22 @@ package sigverify
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"errors"
 	"fmt"
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/storyicon/sigverify/contracts/erc1271"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -30,12 +41,82 @@ func MustMustHexDecode(t *testing.T, data string) []byte {
 	return raw
 }
 
+// simulatedWallets holds the mock ERC1271 wallets deployed to a simulated backend
+type simulatedWallets struct {
+	backend    *backends.SimulatedBackend
+	auth       *bind.TransactOpts
+	ownerKey   *ecdsa.PrivateKey
+	eoa        common.Address
+	owner      common.Address
+	alwaysOK   common.Address
+	reverting  common.Address
+	wrongMagic common.Address
+	guzzler    common.Address
+	legacy     common.Address
+	factory    common.Address
+}
+
+// newSimulatedWallets deploys every mock wallet in contracts/erc1271 to a fresh simulated backend,
+// the owner wallet accepts the signatures of ownerKey.
+func newSimulatedWallets(t *testing.T) *simulatedWallets {
+	deployerKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	ownerKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	deployer := crypto.PubkeyToAddress(deployerKey.PublicKey)
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		deployer: {Balance: new(big.Int).Lsh(big.NewInt(1), 100)},
+	}, 30_000_000)
+	t.Cleanup(func() {
+		_ = backend.Close()
+	})
+	auth, err := bind.NewKeyedTransactorWithChainID(deployerKey, big.NewInt(1337))
+	assert.NoError(t, err)
+
+	w := &simulatedWallets{
+		backend:  backend,
+		auth:     auth,
+		ownerKey: ownerKey,
+		eoa:      crypto.PubkeyToAddress(ownerKey.PublicKey),
+	}
+	w.owner, _, _, err = erc1271.DeployMockERC1271Owner(auth, backend, w.eoa)
+	assert.NoError(t, err)
+	w.alwaysOK, _, _, err = erc1271.DeployMockERC1271AlwaysValid(auth, backend)
+	assert.NoError(t, err)
+	w.reverting, _, _, err = erc1271.DeployMockERC1271Reverting(auth, backend)
+	assert.NoError(t, err)
+	w.wrongMagic, _, _, err = erc1271.DeployMockERC1271WrongMagic(auth, backend)
+	assert.NoError(t, err)
+	w.guzzler, _, _, err = erc1271.DeployMockERC1271GasGuzzler(auth, backend)
+	assert.NoError(t, err)
+	w.legacy, _, _, err = erc1271.DeployMockERC1271LegacyOwner(auth, backend, w.eoa)
+	assert.NoError(t, err)
+	w.factory, _, _, err = erc1271.DeployMockERC1271Factory(auth, backend)
+	assert.NoError(t, err)
+	backend.Commit()
+	return w
+}
+
+// sign signs the personal message data with the key of the owner
+func (w *simulatedWallets) sign(t *testing.T, data []byte) []byte {
+	return w.signHash(t, accounts.TextHash(data))
+}
+
+// signHash signs the 32-byte hash with the key of the owner
+func (w *simulatedWallets) signHash(t *testing.T, hash []byte) []byte {
+	sig, err := crypto.Sign(hash, w.ownerKey)
+	assert.NoError(t, err)
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig
+}
+
 func TestVerifyERC1271HexSignature(t *testing.T) {
-	client, err := ethclient.Dial("https://polygon-rpc.com")
-	assert.Equal(t, nil, err, "failed to connect to mainnet")
+	wallets := newSimulatedWallets(t)
+	data := MustMustHexDecode(t, "0x787177")
+	signature := hexutil.Encode(wallets.sign(t, data))
 	type args struct {
 		ctx       context.Context
-		client    *ethclient.Client
+		caller    bind.ContractCaller
 		address   common.Address
 		data      []byte
 		signature string
@@ -47,50 +128,243 @@ func TestVerifyERC1271HexSignature(t *testing.T) {
 		wantErr assert.ErrorAssertionFunc
 	}{
 		{
+			name: "owner",
 			args: args{
 				ctx:       context.Background(),
-				client:    client,
-				address:   common.HexToAddress("0x4836A472ab1dd406ECb8D0F933A985541ee3921f"),
-				data:      MustMustHexDecode(t, "0x787177"),
-				signature: "0xc0f8db6019888d87a0afc1299e81ef45d3abce64f63072c8d7a6ef00f5f82c1522958ff110afa98b8c0d23b558376db1d2fbab4944e708f8bf6dc7b977ee07201b00",
+				caller:    wallets.backend,
+				address:   wallets.owner,
+				data:      data,
+				signature: signature,
 			},
 			want:    true,
 			wantErr: assert.NoError,
 		},
 		{
+			name: "owner/other message",
+			args: args{
+				ctx:       context.Background(),
+				caller:    wallets.backend,
+				address:   wallets.owner,
+				data:      []byte("other"),
+				signature: signature,
+			},
+			want:    false,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "always valid",
 			args: args{
 				ctx:       context.Background(),
-				client:    client,
-				address:   common.HexToAddress("0x05c57d74256fdb3212da51e70647bf7ab36e8889"),
-				data:      MustMustHexDecode(t, "0x787177"),
-				signature: "0xc0f8db6019888d87a0afc1299e81ef45d3abce64f63072c8d7a6ef00f5f82c1522958ff110afa98b8c0d23b558376db1d2fbab4944e708f8bf6dc7b977ee07201b00",
+				caller:    wallets.backend,
+				address:   wallets.alwaysOK,
+				data:      data,
+				signature: "0x00",
+			},
+			want:    true,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "wrong magic",
+			args: args{
+				ctx:       context.Background(),
+				caller:    wallets.backend,
+				address:   wallets.wrongMagic,
+				data:      data,
+				signature: signature,
+			},
+			want:    false,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "reverting",
+			args: args{
+				ctx:       context.Background(),
+				caller:    wallets.backend,
+				address:   wallets.reverting,
+				data:      data,
+				signature: signature,
 			},
 			want: false,
 			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
-				return IsErrExecutionReverted(err)
+				return assert.True(t, IsErrExecutionReverted(err), i...)
 			},
 		},
 		{
+			name: "gas guzzler",
 			args: args{
 				ctx:       context.Background(),
-				client:    client,
-				address:   common.HexToAddress("0xf4a62fc079f47e9df86ed298f792b3aed13891ed"),
-				data:      MustMustHexDecode(t, "0x787177"),
-				signature: "0xc0f8db6019888d87a0afc1299e81ef45d3abce64f63072c8d7a6ef00f5f82c1522958ff110afa98b8c0d23b558376db1d2fbab4944e708f8bf6dc7b977ee07201b00",
+				caller:    wallets.backend,
+				address:   wallets.guzzler,
+				data:      data,
+				signature: signature,
 			},
 			want: false,
 			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
-				return IsErrNoContractCode(err)
+				return assert.True(t, errors.Is(err, vm.ErrOutOfGas), i...)
+			},
+		},
+		{
+			name: "eoa",
+			args: args{
+				ctx:       context.Background(),
+				caller:    wallets.backend,
+				address:   wallets.eoa,
+				data:      data,
+				signature: signature,
+			},
+			want: false,
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.True(t, IsErrNoContractCode(err), i...)
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := VerifyERC1271HexSignature(tt.args.ctx, tt.args.client, tt.args.address, tt.args.data, tt.args.signature)
-			if !tt.wantErr(t, err, fmt.Sprintf("VerifyERC1271HexSignature(%v, %v, %v, %v, %v)", tt.args.ctx, tt.args.client, tt.args.address, tt.args.data, tt.args.signature)) {
+			got, err := VerifyERC1271HexSignature(tt.args.ctx, tt.args.caller, tt.args.address, tt.args.data, tt.args.signature)
+			if !tt.wantErr(t, err, fmt.Sprintf("VerifyERC1271HexSignature(%v, %v, %v, %v, %v)", tt.args.ctx, tt.args.caller, tt.args.address, tt.args.data, tt.args.signature)) {
 				return
 			}
-			assert.Equalf(t, tt.want, got, "VerifyERC1271HexSignature(%v, %v, %v, %v, %v)", tt.args.ctx, tt.args.client, tt.args.address, tt.args.data, tt.args.signature)
+			assert.Equalf(t, tt.want, got, "VerifyERC1271HexSignature(%v, %v, %v, %v, %v)", tt.args.ctx, tt.args.caller, tt.args.address, tt.args.data, tt.args.signature)
 		})
 	}
 }
+
+// mockContractCaller is a bind.ContractCaller that returns canned results without any network access
+type mockContractCaller struct {
+	code   []byte
+	output []byte
+	err    error
+}
+
+func (m *mockContractCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return m.code, nil
+}
+
+func (m *mockContractCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return m.output, m.err
+}
+
+func TestVerifyERC1271SignatureWithContractCaller(t *testing.T) {
+	magic := GetERC1271Magic()
+	tests := []struct {
+		name    string
+		caller  *mockContractCaller
+		want    bool
+		wantErr error
+	}{
+		{
+			name:   "valid",
+			caller: &mockContractCaller{code: []byte{0x00}, output: common.RightPadBytes(magic[:], 32)},
+			want:   true,
+		},
+		{
+			name:   "wrong magic",
+			caller: &mockContractCaller{code: []byte{0x00}, output: common.RightPadBytes([]byte{0xff, 0xff, 0xff, 0xff}, 32)},
+		},
+		{
+			name:    "eoa",
+			caller:  &mockContractCaller{},
+			wantErr: ErrNoContractCode,
+		},
+		{
+			name:    "reverted",
+			caller:  &mockContractCaller{code: []byte{0x00}, err: errors.New("execution reverted")},
+			wantErr: ErrExecutionReverted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyERC1271Signature(context.Background(), tt.caller, common.Address{1}, []byte("hello"), []byte{0x01})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVerifyERC1271SignatureLegacy(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	legacyMagic := GetERC1271LegacyMagic()
+	assert.Equal(t, crypto.Keccak256([]byte("isValidSignature(bytes,bytes)"))[:4], legacyMagic[:])
+
+	data := []byte("hello")
+	signature := wallets.sign(t, data)
+	// the legacy wallet receives data and signs over the personal message of its hash
+	legacySignature := wallets.sign(t, crypto.Keccak256(data))
+	tests := []struct {
+		name       string
+		address    common.Address
+		signature  []byte
+		opts       []VerifyOption
+		want       bool
+		wantMethod VerificationMethod
+		wantErr    error
+		wantProbed int
+	}{
+		{
+			name:       "legacy probed after bytes32",
+			address:    wallets.legacy,
+			signature:  legacySignature,
+			want:       true,
+			wantMethod: MethodERC1271Legacy,
+			wantProbed: 2,
+		},
+		{
+			name:       "legacy first",
+			address:    wallets.legacy,
+			signature:  legacySignature,
+			opts:       []VerifyOption{WithERC1271Methods(MethodERC1271Legacy, MethodERC1271)},
+			want:       true,
+			wantMethod: MethodERC1271Legacy,
+			wantProbed: 1,
+		},
+		{
+			name:       "bytes32 only",
+			address:    wallets.legacy,
+			signature:  legacySignature,
+			opts:       []VerifyOption{WithERC1271Methods(MethodERC1271)},
+			wantErr:    ErrExecutionReverted,
+			wantProbed: 1,
+		},
+		{
+			name:       "bytes32 wallet with legacy first",
+			address:    wallets.owner,
+			signature:  signature,
+			opts:       []VerifyOption{WithERC1271Methods(MethodERC1271Legacy, MethodERC1271)},
+			want:       true,
+			wantMethod: MethodERC1271,
+			wantProbed: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := VerifyERC1271SignatureDetailed(context.Background(), wallets.backend, tt.address, data, tt.signature, tt.opts...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, result.Valid)
+			assert.Equal(t, tt.wantMethod, result.Method)
+			probed := len(result.Failures)
+			if result.Valid {
+				probed++
+			}
+			assert.Equal(t, tt.wantProbed, probed)
+		})
+	}
+
+	result, err := VerifySignatureExDetailed(context.Background(), wallets.backend, wallets.legacy, data, legacySignature)
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, MethodERC1271Legacy, result.Method)
+
+	hash := crypto.Keccak256Hash(data)
+	result, err = VerifyHashSignatureExDetailed(context.Background(), wallets.backend, wallets.legacy, hash, wallets.sign(t, crypto.Keccak256(hash[:])))
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, MethodERC1271Legacy, result.Method)
+}
@@ -0,0 +1,143 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// magicSuffixERC6492 is appended to the signatures of counterfactual wallets, which is defined in ERC6492
+var magicSuffixERC6492 = ethcommon.FromHex("0x6492649264926492649264926492649264926492649264926492649264926492")
+
+// erc6492Arguments is abi.encode(address create2Factory, bytes factoryCalldata, bytes originalSignature)
+var erc6492Arguments = abi.Arguments{
+	{Type: mustNewType("address")},
+	{Type: mustNewType("bytes")},
+	{Type: mustNewType("bytes")},
+}
+
+// erc6492ValidatorCode is the init code of a deployless validator, it is executed by eth_call without a
+// "to" address. The arguments are appended to the code as
+// signer | hash | factory | len(factoryCalldata) | len(signature) | factoryCalldata | signature,
+// each of the first five is a 32-byte word. The validator calls factory with factoryCalldata
+// if the signer has no code yet, then returns or reverts with whatever isValidSignature(hash, signature)
+// of the signer returns or reverts with:
+//
+//	PUSH2 end CODESIZE SUB PUSH2 end PUSH1 0 CODECOPY          ; copy the arguments to memory
+//	PUSH1 0 MLOAD EXTCODESIZE PUSH1 deployed JUMPI
+//	PUSH1 0 PUSH1 0 PUSH1 0x60 MLOAD PUSH1 0xa0 PUSH1 0 PUSH1 0x40 MLOAD GAS CALL
+//	PUSH1 deployed JUMPI                                       ; bubble up the revert of the factory
+//	RETURNDATASIZE PUSH1 0 PUSH1 0 RETURNDATACOPY RETURNDATASIZE PUSH1 0 REVERT
+//	deployed:                                                  ; isValidSignature(hash, signature)
+//	PUSH1 0x80 MLOAD PUSH1 0x60 MLOAD ADD PUSH1 0xa0 ADD
+//	PUSH4 0x1626ba7e PUSH1 0xe0 SHL DUP2 MSTORE
+//	PUSH1 0x20 MLOAD DUP2 PUSH1 0x04 ADD MSTORE
+//	PUSH1 0x40 DUP2 PUSH1 0x24 ADD MSTORE
+//	PUSH1 0x80 MLOAD DUP2 PUSH1 0x44 ADD MSTORE
+//	PUSH1 0x80 MLOAD PUSH1 0x60 MLOAD PUSH1 0xa0 ADD PUSH2 end ADD DUP3 PUSH1 0x64 ADD CODECOPY
+//	PUSH1 0 PUSH1 0 PUSH1 0x80 MLOAD PUSH1 0x64 ADD DUP4 PUSH1 0 MLOAD GAS STATICCALL
+//	RETURNDATASIZE PUSH1 0 PUSH1 0 RETURNDATACOPY
+//	PUSH1 ok JUMPI
+//	RETURNDATASIZE PUSH1 0 REVERT
+//	ok:
+//	RETURNDATASIZE PUSH1 0 RETURN
+//	end:
+var erc6492ValidatorCode = hexutil.MustDecode("0x61008f380361008f6000396000513b602f576000600060605160a060006040515af1602f573d600060003e3d6000fd5b6080516060510160a001631626ba7e60e01b8152602051816004015260408160240152608051816044015260805160605160a00161008f01826064013960006000608051606401836000515afa3d600060003e608a573d6000fd5b3d6000f3")
+
+func mustNewType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// IsERC6492Signature reports whether the signature ends with the magic suffix defined by ERC6492,
+// which means it is signed by a wallet that may not be deployed yet.
+func IsERC6492Signature(signature []byte) bool {
+	return len(signature) >= len(magicSuffixERC6492) && bytes.HasSuffix(signature, magicSuffixERC6492)
+}
+
+// ParseERC6492Signature unwraps an ERC6492 signature, and returns the factory that deploys the wallet,
+// the calldata sent to the factory and the original signature of the wallet.
+func ParseERC6492Signature(signature []byte) (factory ethcommon.Address, factoryCalldata []byte, originalSignature []byte, err error) {
+	if !IsERC6492Signature(signature) {
+		return ethcommon.Address{}, nil, nil, errors.New("invalid ERC6492 signature: missing magic suffix")
+	}
+	values, err := erc6492Arguments.Unpack(signature[:len(signature)-len(magicSuffixERC6492)])
+	if err != nil {
+		return ethcommon.Address{}, nil, nil, fmt.Errorf("invalid ERC6492 signature: %w", err)
+	}
+	return values[0].(ethcommon.Address), values[1].([]byte), values[2].([]byte), nil
+}
+
+// WrapERC6492Signature wraps the signature of a counterfactual wallet as defined by ERC6492,
+// factoryCalldata is sent to factory to deploy the wallet before isValidSignature is called.
+func WrapERC6492Signature(factory ethcommon.Address, factoryCalldata []byte, signature []byte) ([]byte, error) {
+	wrapped, err := erc6492Arguments.Pack(factory, factoryCalldata, signature)
+	if err != nil {
+		return nil, err
+	}
+	return append(wrapped, magicSuffixERC6492...), nil
+}
+
+// verifyERC6492Digest calls the deployless validator with the unwrapped signature
+func verifyERC6492Digest(ctx context.Context, caller bind.ContractCaller, result *VerificationResult, signature []byte, options *verifyOptions) error {
+	factory, factoryCalldata, originalSignature, err := ParseERC6492Signature(signature)
+	if err != nil {
+		result.fail(MethodERC6492, err)
+		return err
+	}
+	output, err := caller.CallContract(ctx, ethereum.CallMsg{
+		Data: erc6492ValidatorInput(result.Address, result.Digest, factory, factoryCalldata, originalSignature),
+	}, nil)
+	if err != nil {
+		err = wrapCallError(err, options.errorABIs...)
+		result.fail(MethodERC6492, err)
+		return err
+	}
+	var magic [4]byte
+	if len(output) >= 32 {
+		copy(magic[:], output)
+	}
+	if magic != GetERC1271Magic() {
+		result.fail(MethodERC6492, fmt.Errorf("%w: isValidSignature returned 0x%x", ErrInvalidMagicValue, output))
+		return nil
+	}
+	result.succeed(MethodERC6492)
+	return nil
+}
+
+func erc6492ValidatorInput(signer ethcommon.Address, hash ethcommon.Hash, factory ethcommon.Address, factoryCalldata []byte, signature []byte) []byte {
+	input := make([]byte, 0, len(erc6492ValidatorCode)+5*32+len(factoryCalldata)+len(signature))
+	input = append(input, erc6492ValidatorCode...)
+	input = append(input, ethcommon.LeftPadBytes(signer[:], 32)...)
+	input = append(input, hash[:]...)
+	input = append(input, ethcommon.LeftPadBytes(factory[:], 32)...)
+	input = append(input, ethcommon.LeftPadBytes(big.NewInt(int64(len(factoryCalldata))).Bytes(), 32)...)
+	input = append(input, ethcommon.LeftPadBytes(big.NewInt(int64(len(signature))).Bytes(), 32)...)
+	input = append(input, factoryCalldata...)
+	return append(input, signature...)
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/storyicon/sigverify/contracts/erc1271"
+	"github.com/stretchr/testify/assert"
+)
+
+// counterfactual returns the address that the mock factory deploys the owner wallet of owner to,
+// together with the calldata that deploys it
+func (w *simulatedWallets) counterfactual(t *testing.T, owner common.Address) (common.Address, []byte) {
+	factoryABI, err := abi.JSON(strings.NewReader(erc1271.MockERC1271FactoryMetaData.ABI))
+	assert.NoError(t, err)
+	calldata, err := factoryABI.Pack("deploy", owner)
+	assert.NoError(t, err)
+	ownerABI, err := abi.JSON(strings.NewReader(erc1271.MockERC1271OwnerMetaData.ABI))
+	assert.NoError(t, err)
+	args, err := ownerABI.Pack("", owner)
+	assert.NoError(t, err)
+	initCode := append(common.FromHex(erc1271.MockERC1271OwnerMetaData.Bin), args...)
+	var salt [32]byte
+	copy(salt[12:], owner[:])
+	return crypto.CreateAddress2(w.factory, salt, crypto.Keccak256(initCode)), calldata
+}
+
+func TestERC6492Signature(t *testing.T) {
+	factory := common.HexToAddress("0x0000000000ffe8b47b3e2130213b802212439497")
+	calldata := []byte{0x01, 0x02, 0x03}
+	signature := []byte("signature")
+	wrapped, err := WrapERC6492Signature(factory, calldata, signature)
+	assert.NoError(t, err)
+	assert.True(t, IsERC6492Signature(wrapped))
+	assert.False(t, IsERC6492Signature(signature))
+	assert.False(t, IsERC6492Signature(wrapped[:len(wrapped)-1]))
+
+	gotFactory, gotCalldata, gotSignature, err := ParseERC6492Signature(wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, factory, gotFactory)
+	assert.Equal(t, calldata, gotCalldata)
+	assert.Equal(t, signature, gotSignature)
+
+	_, _, _, err = ParseERC6492Signature(signature)
+	assert.Error(t, err)
+	_, _, _, err = ParseERC6492Signature(append([]byte{0x01}, magicSuffixERC6492...))
+	assert.Error(t, err)
+}
+
+func TestVerifyERC6492Signature(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	data := []byte("hello")
+	wallet, calldata := wallets.counterfactual(t, wallets.eoa)
+	code, err := wallets.backend.CodeAt(context.Background(), wallet, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, code)
+
+	wrap := func(factory common.Address, calldata []byte, signature []byte) []byte {
+		wrapped, err := WrapERC6492Signature(factory, calldata, signature)
+		assert.NoError(t, err)
+		return wrapped
+	}
+	otherKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	otherSignature, err := crypto.Sign(accounts.TextHash(data), otherKey)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		signature  []byte
+		want       bool
+		wantMethod VerificationMethod
+		wantErr    error
+	}{
+		{
+			name:       "counterfactual",
+			signature:  wrap(wallets.factory, calldata, wallets.sign(t, data)),
+			want:       true,
+			wantMethod: MethodERC6492,
+		},
+		{
+			name:      "counterfactual/signed by another key",
+			signature: wrap(wallets.factory, calldata, otherSignature),
+		},
+		{
+			name:      "counterfactual/without wrapping",
+			signature: wallets.sign(t, data),
+			wantErr:   ErrNoContractCode,
+		},
+		{
+			name:      "factory reverted",
+			signature: wrap(wallets.reverting, calldata, wallets.sign(t, data)),
+			wantErr:   ErrExecutionReverted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := VerifySignatureExDetailed(context.Background(), wallets.backend, wallet, data, tt.signature)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, result.Valid)
+			assert.Equal(t, tt.wantMethod, result.Method)
+
+			valid, err := VerifyERC1271Signature(context.Background(), wallets.backend, wallet, data, tt.signature)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, valid)
+		})
+	}
+
+	// the wrapped signature is still valid once the wallet is deployed
+	factory, err := erc1271.NewMockERC1271Factory(wallets.factory, wallets.backend)
+	assert.NoError(t, err)
+	_, err = factory.Deploy(wallets.auth, wallets.eoa)
+	assert.NoError(t, err)
+	wallets.backend.Commit()
+	code, err = wallets.backend.CodeAt(context.Background(), wallet, nil)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, code)
+	for _, signature := range [][]byte{wrap(wallets.factory, calldata, wallets.sign(t, data)), wallets.sign(t, data)} {
+		valid, err := VerifySignatureEx(context.Background(), wallets.backend, wallet, data, signature)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+	}
+}
@@ -15,15 +15,197 @@
 package sigverify
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
 	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
+var (
+	// ErrInvalidSignatureLength is returned when a signature does not have a supported length
+	ErrInvalidSignatureLength = errors.New("invalid signature length")
+
+	// ErrInvalidRecoveryID is returned when the V value of a signature is not supported
+	ErrInvalidRecoveryID = errors.New("invalid Ethereum signature")
+
+	// ErrHighS is returned in strict mode when the s value of a signature is greater than secp256k1n/2,
+	// use NormalizeSignature to get the canonical form of such a signature.
+	ErrHighS = errors.New("invalid signature: s value is greater than secp256k1n/2")
+
+	// ErrNonCanonicalSignature is returned in strict mode by the Ex functions when a signature is in a form other than
+	// the 65-byte r || s || v with v 27 or 28, such as the EIP-2098 compact form, the ledger v of 0 or 1 or
+	// an EIP-155 v when no chain id is expected, each of which is another encoding of the same signature.
+	ErrNonCanonicalSignature = errors.New("invalid signature: non-canonical encoding")
+
+	// ErrChainIDMismatch is returned when the chain id encoded in an EIP-155 style v value is not the expected one
+	ErrChainIDMismatch = errors.New("chain id mismatch")
+
+	// ErrAddressMismatch is recorded in VerificationResult when the recovered signer is not the expected address
+	ErrAddressMismatch = errors.New("recovered address does not match")
+
+	// ErrInvalidMagicValue is recorded in VerificationResult when isValidSignature returns a value other than the ERC1271 magic
+	ErrInvalidMagicValue = errors.New("invalid ERC1271 magic value")
+
+	// ErrNoContractCode is returned when the address being called has no code, which usually means it is an EOA.
+	// It is the same error as bind.ErrNoCode.
+	ErrNoContractCode = bind.ErrNoCode
+
+	// ErrNoBlockHashState is returned when the contract calls are pinned to a block hash,
+	// but the caller does not implement BlockHashContractCaller
+	ErrNoBlockHashState = errors.New("backend does not support calls at a block hash")
+
+	// ErrExecutionReverted is returned when the contract call reverts, use errors.As with *RevertError to get the revert data
+	ErrExecutionReverted = errors.New("execution reverted")
+)
+
+// RevertError is returned when a contract call reverts, errors.Is(err, ErrExecutionReverted) reports true for it.
+type RevertError struct {
+	// Data is the raw revert data, it is empty if the RPC provider does not return it
+	Data []byte
+	// Reason is the decoded Error(string) reason, it is empty if the revert data is not Error(string)
+	Reason string
+	// PanicCode is the decoded Panic(uint256) code raised by a failed assert, an arithmetic overflow and so on,
+	// it is nil if the revert data is not Panic(uint256)
+	PanicCode *big.Int
+	// CustomError is the custom error that the revert data matches among the ABIs given by WithErrorABIs,
+	// it is nil if none matches
+	CustomError *abi.Error
+	// CustomErrorArgs are the decoded arguments of CustomError
+	CustomErrorArgs []interface{}
+	// Err is the original error returned by the RPC provider
+	Err error
+}
+
+// Error implements the error interface
+func (e *RevertError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("execution reverted: %s", e.Reason)
+	}
+	if e.PanicCode != nil {
+		return fmt.Sprintf("execution reverted: panic 0x%x (%s)", e.PanicCode, panicDescription(e.PanicCode))
+	}
+	if e.CustomError != nil {
+		args := make([]string, len(e.CustomErrorArgs))
+		for i, arg := range e.CustomErrorArgs {
+			args[i] = fmt.Sprintf("%v", arg)
+		}
+		return fmt.Sprintf("execution reverted: %s(%s)", e.CustomError.Name, strings.Join(args, ", "))
+	}
+	if len(e.Data) > 0 {
+		return fmt.Sprintf("execution reverted: 0x%x", e.Data)
+	}
+	if e.Err == nil || strings.HasPrefix(e.Err.Error(), "execution reverted") {
+		return "execution reverted"
+	}
+	return fmt.Sprintf("execution reverted: %v", e.Err)
+}
+
+// Unwrap returns the original error returned by the RPC provider
+func (e *RevertError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, ErrExecutionReverted) report true
+func (e *RevertError) Is(target error) bool {
+	return target == ErrExecutionReverted
+}
+
+// wrapCallError converts the error of a contract call into the typed errors of this package,
+// the revert data is decoded with the custom errors of errorABIs in addition to Error(string) and Panic(uint256)
+func wrapCallError(err error, errorABIs ...abi.ABI) error {
+	if err == nil || errors.Is(err, ErrNoContractCode) {
+		return err
+	}
+	var revertErr *RevertError
+	if errors.As(err, &revertErr) {
+		return err
+	}
+	var rpcErr rpc.Error
+	isReverted := errors.Is(err, vm.ErrExecutionReverted) ||
+		(errors.As(err, &rpcErr) && rpcErr.ErrorCode() == 3) ||
+		strings.Contains(strings.ToLower(err.Error()), "revert")
+	if !isReverted {
+		return err
+	}
+	revertErr = &RevertError{Err: err}
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			revertErr.Data, _ = HexDecode(data)
+		}
+	}
+	revertErr.decode(errorABIs)
+	return revertErr
+}
+
+// panicSelector is bytes4(keccak256("Panic(uint256)"))
+var panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+
+// panicDescriptions are the Panic(uint256) codes defined by solidity
+var panicDescriptions = map[uint64]string{
+	0x00: "generic compiler inserted panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid encoded storage byte array",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to uninitialized function",
+}
+
+func panicDescription(code *big.Int) string {
+	if code.IsUint64() {
+		if description, ok := panicDescriptions[code.Uint64()]; ok {
+			return description
+		}
+	}
+	return "unknown panic code"
+}
+
+// decode decodes e.Data as Error(string), Panic(uint256) or one of the custom errors of errorABIs
+func (e *RevertError) decode(errorABIs []abi.ABI) {
+	if len(e.Data) < 4 {
+		return
+	}
+	if reason, err := abi.UnpackRevert(e.Data); err == nil {
+		e.Reason = reason
+		return
+	}
+	if bytes.Equal(e.Data[:4], panicSelector) && len(e.Data) == 36 {
+		e.PanicCode = new(big.Int).SetBytes(e.Data[4:])
+		return
+	}
+	for _, errorABI := range errorABIs {
+		for _, customError := range errorABI.Errors {
+			if !bytes.Equal(e.Data[:4], customError.ID[:4]) {
+				continue
+			}
+			args, err := customError.Inputs.Unpack(e.Data[4:])
+			if err != nil {
+				continue
+			}
+			customError := customError
+			e.CustomError = &customError
+			e.CustomErrorArgs = args
+			return
+		}
+	}
+}
+
 // IsErrExecutionReverted is used to determine whether err is an ExecutionReverted error
 func IsErrExecutionReverted(err error) bool {
-	return err != nil && strings.HasPrefix(err.Error(), "execution reverted")
+	return errors.Is(err, ErrExecutionReverted) || (err != nil && strings.HasPrefix(err.Error(), "execution reverted"))
 }
 
 // IsErrNoContractCode is used to determine whether err is an NoContractCode error
 func IsErrNoContractCode(err error) bool {
-	return err != nil && strings.HasPrefix(err.Error(), "no contract code at given address")
+	return errors.Is(err, ErrNoContractCode) || (err != nil && strings.HasPrefix(err.Error(), "no contract code at given address"))
 }
@@ -0,0 +1,168 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type testDataError struct {
+	code    int
+	message string
+	data    interface{}
+}
+
+func (e *testDataError) Error() string          { return e.message }
+func (e *testDataError) ErrorCode() int         { return e.code }
+func (e *testDataError) ErrorData() interface{} { return e.data }
+
+func TestWrapCallError(t *testing.T) {
+	// Error("GS026")
+	reason := "0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000005" +
+		"4753303236000000000000000000000000000000000000000000000000000000"
+	tests := []struct {
+		name       string
+		err        error
+		wantRevert bool
+		wantNoCode bool
+		wantReason string
+	}{
+		{
+			name:       "geth revert with reason",
+			err:        &testDataError{code: 3, message: "execution reverted: GS026", data: reason},
+			wantRevert: true,
+			wantReason: "GS026",
+		},
+		{
+			name:       "provider specific wording",
+			err:        errors.New("VM Exception while processing transaction: revert"),
+			wantRevert: true,
+		},
+		{
+			name:       "no code",
+			err:        fmt.Errorf("call failed: %w", ErrNoContractCode),
+			wantNoCode: true,
+		},
+		{
+			name: "network",
+			err:  errors.New("dial tcp: connection refused"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wrapCallError(tt.err)
+			assert.Equal(t, tt.wantRevert, errors.Is(err, ErrExecutionReverted))
+			assert.Equal(t, tt.wantRevert, IsErrExecutionReverted(err))
+			assert.Equal(t, tt.wantNoCode, IsErrNoContractCode(err))
+			assert.ErrorIs(t, err, tt.err)
+			var revertErr *RevertError
+			if errors.As(err, &revertErr) {
+				assert.Equal(t, tt.wantReason, revertErr.Reason)
+				if tt.wantReason != "" {
+					assert.Equal(t, reason, hexutil.Encode(revertErr.Data))
+				}
+			}
+		})
+	}
+}
+
+func TestRevertErrorDecode(t *testing.T) {
+	errorABI, err := abi.JSON(strings.NewReader(`[{"inputs":[{"internalType":"address","name":"owner","type":"address"}],"name":"InvalidOwner","type":"error"}]`))
+	assert.NoError(t, err)
+	invalidOwner := errorABI.Errors["InvalidOwner"]
+	args, err := invalidOwner.Inputs.Pack(common.HexToAddress("0x545087bd36c7F0eFaeC26252Ee62085CA9A726AC"))
+	assert.NoError(t, err)
+	customData := append(invalidOwner.ID[:4:4], args...)
+
+	tests := []struct {
+		name          string
+		data          string
+		errorABIs     []abi.ABI
+		wantMessage   string
+		wantPanicCode *big.Int
+		wantCustom    string
+	}{
+		{
+			name:        "reason",
+			data:        "0x08c379a0000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000054753303236000000000000000000000000000000000000000000000000000000",
+			wantMessage: "execution reverted: GS026",
+		},
+		{
+			name:          "panic",
+			data:          "0x4e487b710000000000000000000000000000000000000000000000000000000000000011",
+			wantMessage:   "execution reverted: panic 0x11 (arithmetic underflow or overflow)",
+			wantPanicCode: big.NewInt(0x11),
+		},
+		{
+			name:        "custom error",
+			data:        hexutil.Encode(customData),
+			errorABIs:   []abi.ABI{errorABI},
+			wantMessage: "execution reverted: InvalidOwner(0x545087bd36c7F0eFaeC26252Ee62085CA9A726AC)",
+			wantCustom:  "InvalidOwner",
+		},
+		{
+			name:        "custom error without abi",
+			data:        hexutil.Encode(customData),
+			wantMessage: "execution reverted: " + hexutil.Encode(customData),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wrapCallError(&testDataError{code: 3, message: "execution reverted", data: tt.data}, tt.errorABIs...)
+			var revertErr *RevertError
+			assert.True(t, errors.As(err, &revertErr))
+			assert.Equal(t, tt.wantMessage, err.Error())
+			assert.Equal(t, tt.wantPanicCode, revertErr.PanicCode)
+			if tt.wantCustom != "" {
+				assert.Equal(t, tt.wantCustom, revertErr.CustomError.Name)
+				assert.Len(t, revertErr.CustomErrorArgs, 1)
+			} else {
+				assert.Nil(t, revertErr.CustomError)
+			}
+		})
+	}
+
+	// the reason of a reverting wallet is decoded from the simulated backend
+	wallets := newSimulatedWallets(t)
+	_, err = VerifyERC1271Signature(context.Background(), wallets.backend, wallets.reverting, []byte("hello"), wallets.sign(t, []byte("hello")))
+	var revertErr *RevertError
+	assert.True(t, errors.As(err, &revertErr))
+	assert.Equal(t, "MockERC1271: invalid signature", revertErr.Reason)
+}
+
+func TestSentinelErrors(t *testing.T) {
+	_, err := EcRecoverEx([]byte("hello"), []byte{1, 2, 3})
+	assert.ErrorIs(t, err, ErrInvalidSignatureLength)
+
+	sig := MustMustHexDecode(t, "0x0498c6564863c78e663848b963fde1ea1d860d5d882d2abdb707d1e9179ff80630a4a71705da534a562c08cb64a546c6132de26eb77a44f086832cbc1dbe01f705")
+	_, err = EcRecoverEx([]byte("hello"), sig)
+	assert.ErrorIs(t, err, ErrInvalidRecoveryID)
+
+	result, err := VerifyEllipticCurveSignatureExDetailed([20]byte{}, []byte("hello"), sig[:64])
+	assert.NoError(t, err)
+	assert.ErrorIs(t, result.Failures[0], ErrAddressMismatch)
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// HashEncoding describes how a 32-byte hash was turned into the digest that was signed
+type HashEncoding string
+
+const (
+	// HashEncodingRaw means the hash itself was signed, as done by the legacy eth_sign of old wallets,
+	// some MPC custodians and the contracts that call ecrecover on the hash directly,
+	// use VerifyRawHashSignature when only this encoding is accepted
+	HashEncodingRaw HashEncoding = "raw"
+	// HashEncodingPersonal means the hash was signed as a personal message, the digest is
+	// keccak256("\x19Ethereum Signed Message:\n32" || hash)
+	HashEncodingPersonal HashEncoding = "personal"
+)
+
+// VerifyRawHashSignature verifies an eth_sign signature made over a raw 32-byte hash without the personal message prefix,
+// it recovers the signer of hash and falls back to isValidSignature(hash, signature) of ERC1271.
+// It is the same as VerifyHashSignatureEx, named after the use case.
+func VerifyRawHashSignature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, hash [32]byte, signature []byte, opts ...VerifyOption) (bool, error) {
+	return VerifyHashSignatureEx(ctx, caller, address, hash, signature, opts...)
+}
+
+// VerifyRawHashSignatureDetailed is like VerifyRawHashSignature, but returns a VerificationResult
+// that explains how the signature was verified.
+func VerifyRawHashSignatureDetailed(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, hash [32]byte, signature []byte, opts ...VerifyOption) (*VerificationResult, error) {
+	return VerifyHashSignatureExDetailed(ctx, caller, address, hash, signature, opts...)
+}
+
+// VerifyRawHashHexSignature is like VerifyRawHashSignature but accepts a hex encoded hash and a hex encoded signature
+func VerifyRawHashHexSignature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, hash string, signature string, opts ...VerifyOption) (bool, error) {
+	digest, err := decodeHash(hash)
+	if err != nil {
+		return false, err
+	}
+	return VerifyHashHexSignatureEx(ctx, caller, address, digest, signature, opts...)
+}
+
+// VerifyRawOrPrefixedHashSignature verifies a signature over a 32-byte hash that may have been signed either raw or
+// as a personal message, because wallets disagree on what eth_sign does. It returns the HashEncoding that matched,
+// which is "" when the signature is not valid for either.
+// The elliptic curve verification of both encodings is tried before falling back to ERC1271 with the raw hash and then
+// with the prefixed one, so that an EOA never costs an eth_call.
+func VerifyRawOrPrefixedHashSignature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, hash [32]byte, signature []byte, opts ...VerifyOption) (bool, HashEncoding, error) {
+	result, encoding, err := VerifyRawOrPrefixedHashSignatureDetailed(ctx, caller, address, hash, signature, opts...)
+	return result.Valid, encoding, err
+}
+
+// VerifyRawOrPrefixedHashSignatureDetailed is like VerifyRawOrPrefixedHashSignature, but returns a VerificationResult
+// that explains how the signature was verified, its Digest is the one of the HashEncoding that matched.
+func VerifyRawOrPrefixedHashSignatureDetailed(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, hash [32]byte, signature []byte, opts ...VerifyOption) (*VerificationResult, HashEncoding, error) {
+	encodings := []HashEncoding{HashEncodingRaw, HashEncodingPersonal}
+	digests := []signedDigest{
+		{hash: hash},
+		textDigest(hash[:]),
+	}
+	result, i, err := verifyHashCandidates(ctx, caller, address, digests, signature, newVerifyOptions(opts))
+	if i < 0 {
+		return result, "", err
+	}
+	return result, encodings[i], err
+}
+
+// VerifyRawOrPrefixedHashHexSignature is like VerifyRawOrPrefixedHashSignature but accepts a hex encoded hash
+// and a hex encoded signature
+func VerifyRawOrPrefixedHashHexSignature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, hash string, signature string, opts ...VerifyOption) (bool, HashEncoding, error) {
+	digest, err := decodeHash(hash)
+	if err != nil {
+		return false, "", err
+	}
+	sig, err := HexDecode(signature)
+	if err != nil {
+		return false, "", err
+	}
+	return VerifyRawOrPrefixedHashSignature(ctx, caller, address, digest, sig, opts...)
+}
+
+// decodeHash decodes a hex encoded 32-byte hash
+func decodeHash(s string) ([32]byte, error) {
+	data, err := HexDecode(s)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if len(data) != ethcommon.HashLength {
+		return [32]byte{}, fmt.Errorf("hash has %d bytes, expected %d", len(data), ethcommon.HashLength)
+	}
+	return ethcommon.BytesToHash(data), nil
+}
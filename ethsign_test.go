@@ -0,0 +1,87 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyRawHashSignature(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	ctx := context.Background()
+	hash := crypto.Keccak256Hash([]byte("order"))
+	signature := wallets.signHash(t, hash[:])
+
+	valid, err := VerifyRawHashSignature(ctx, wallets.backend, wallets.eoa, hash, signature)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = VerifyRawHashHexSignature(ctx, wallets.backend, wallets.owner, hash.Hex(), hexutil.Encode(signature))
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	result, err := VerifyRawHashSignatureDetailed(ctx, wallets.backend, wallets.eoa, hash, wallets.sign(t, hash[:]))
+	assert.True(t, IsErrNoContractCode(err))
+	assert.False(t, result.Valid)
+
+	_, err = VerifyRawHashHexSignature(ctx, wallets.backend, wallets.eoa, "0x1234", hexutil.Encode(signature))
+	assert.Error(t, err)
+}
+
+func TestVerifyRawOrPrefixedHashSignature(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	hash := crypto.Keccak256Hash([]byte("order"))
+	raw := wallets.signHash(t, hash[:])
+	prefixed := wallets.sign(t, hash[:])
+	other := wallets.signHash(t, make([]byte, 32))
+	tests := []struct {
+		name         string
+		address      common.Address
+		signature    []byte
+		want         bool
+		wantEncoding HashEncoding
+		wantMethod   VerificationMethod
+		wantDigest   []byte
+	}{
+		{"ecdsa/raw", wallets.eoa, raw, true, HashEncodingRaw, MethodECDSA, hash[:]},
+		{"ecdsa/personal", wallets.eoa, prefixed, true, HashEncodingPersonal, MethodECDSA, accounts.TextHash(hash[:])},
+		{"ecdsa/neither", wallets.eoa, other, false, "", "", nil},
+		{"erc1271/raw", wallets.owner, raw, true, HashEncodingRaw, MethodERC1271, hash[:]},
+		{"erc1271/personal", wallets.owner, prefixed, true, HashEncodingPersonal, MethodERC1271, accounts.TextHash(hash[:])},
+		{"erc1271/neither", wallets.wrongMagic, other, false, "", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, encoding, _ := VerifyRawOrPrefixedHashSignatureDetailed(context.Background(), wallets.backend, tt.address, hash, tt.signature)
+			assert.Equal(t, tt.want, result.Valid)
+			assert.Equal(t, tt.wantEncoding, encoding)
+			assert.Equal(t, tt.wantMethod, result.Method)
+			if tt.wantDigest != nil {
+				assert.Equal(t, common.BytesToHash(tt.wantDigest), result.Digest)
+			}
+
+			valid, encoding, _ := VerifyRawOrPrefixedHashHexSignature(context.Background(), wallets.backend, tt.address, hash.Hex(), hexutil.Encode(tt.signature))
+			assert.Equal(t, tt.want, valid)
+			assert.Equal(t, tt.wantEncoding, encoding)
+		})
+	}
+}
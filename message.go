@@ -0,0 +1,143 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// MessageEncoding is an interpretation of a personal message, which decides the bytes that the wallet signed
+type MessageEncoding string
+
+const (
+	// MessageRaw is the message as given
+	MessageRaw MessageEncoding = "raw"
+	// MessageHexDecoded is the bytes decoded from a message that is a 0x prefixed hex string,
+	// as MetaMask's personal_sign does with such messages
+	MessageHexDecoded MessageEncoding = "hex-decoded"
+	// MessageHexString is the UTF-8 of the 0x prefixed hex string of the message,
+	// as signed by the wallets that were handed the hex string and signed it literally
+	MessageHexString MessageEncoding = "hex-string"
+	// MessageLF is the message with its CRLF line endings replaced by LF
+	MessageLF MessageEncoding = "lf"
+	// MessageCRLF is the message with its LF line endings replaced by CRLF
+	MessageCRLF MessageEncoding = "crlf"
+)
+
+// MessagePolicy is the ordered list of the MessageEncoding that may be accepted for a personal message.
+// Every accepted encoding is another message the same signature is valid for, so the caller should act on
+// the interpretation that matched, and keep the policy as narrow as the wallets it serves allow.
+type MessagePolicy []MessageEncoding
+
+var (
+	// StrictMessagePolicy only accepts the message as given, which is what VerifySignatureEx does
+	StrictMessagePolicy = MessagePolicy{MessageRaw}
+	// HexMessagePolicy accepts the message as given and the hex interpretations that wallets disagree on
+	HexMessagePolicy = MessagePolicy{MessageRaw, MessageHexDecoded, MessageHexString}
+	// LenientMessagePolicy accepts every MessageEncoding
+	LenientMessagePolicy = MessagePolicy{MessageRaw, MessageHexDecoded, MessageHexString, MessageLF, MessageCRLF}
+)
+
+// Interpret returns the bytes of msg under the encoding, ok is false when the encoding does not apply to msg,
+// such as MessageHexDecoded for a message that is not a hex string or MessageLF for a message without CRLF
+func (e MessageEncoding) Interpret(msg []byte) ([]byte, bool, error) {
+	switch e {
+	case MessageRaw:
+		return msg, true, nil
+	case MessageHexDecoded:
+		decoded, err := hexutil.Decode(string(msg))
+		if err != nil {
+			return nil, false, nil
+		}
+		return decoded, true, nil
+	case MessageHexString:
+		return []byte(hexutil.Encode(msg)), true, nil
+	case MessageLF:
+		if !bytes.Contains(msg, []byte("\r\n")) {
+			return nil, false, nil
+		}
+		return bytes.ReplaceAll(msg, []byte("\r\n"), []byte("\n")), true, nil
+	case MessageCRLF:
+		lf := bytes.ReplaceAll(msg, []byte("\r\n"), []byte("\n"))
+		if !bytes.Contains(lf, []byte("\n")) {
+			return nil, false, nil
+		}
+		crlf := bytes.ReplaceAll(lf, []byte("\n"), []byte("\r\n"))
+		if bytes.Equal(crlf, msg) {
+			return nil, false, nil
+		}
+		return crlf, true, nil
+	default:
+		return nil, false, fmt.Errorf("unsupported message encoding %q", e)
+	}
+}
+
+// VerifyMessageSignature is like VerifySignatureEx, but verifies the personal message under every MessageEncoding
+// of policy in order, and returns the encoding that verified, which is "" when none does.
+// The encodings that do not apply to msg and those that yield the same bytes as an earlier one are skipped.
+// The elliptic curve verification of every interpretation is tried before any contract is called.
+func VerifyMessageSignature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, msg []byte, signature []byte, policy MessagePolicy, opts ...VerifyOption) (bool, MessageEncoding, error) {
+	result, encoding, err := VerifyMessageSignatureDetailed(ctx, caller, address, msg, signature, policy, opts...)
+	return result.Valid, encoding, err
+}
+
+// VerifyMessageSignatureDetailed is like VerifyMessageSignature, but returns a VerificationResult
+// that explains how the signature was verified, its Digest is the one of the MessageEncoding that verified.
+func VerifyMessageSignatureDetailed(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, msg []byte, signature []byte, policy MessagePolicy, opts ...VerifyOption) (*VerificationResult, MessageEncoding, error) {
+	var (
+		encodings []MessageEncoding
+		digests   []signedDigest
+		seen      = make(map[[32]byte]bool)
+	)
+	for _, encoding := range policy {
+		interpreted, ok, err := encoding.Interpret(msg)
+		if err != nil {
+			return &VerificationResult{Address: address}, "", err
+		}
+		if !ok {
+			continue
+		}
+		digest := textDigest(interpreted)
+		if seen[digest.hash] {
+			continue
+		}
+		seen[digest.hash] = true
+		encodings = append(encodings, encoding)
+		digests = append(digests, digest)
+	}
+	if len(digests) == 0 {
+		return &VerificationResult{Address: address}, "", fmt.Errorf("no message encoding of the policy %v applies to the message", policy)
+	}
+	result, i, err := verifyHashCandidates(ctx, caller, address, digests, signature, newVerifyOptions(opts))
+	if i < 0 {
+		return result, "", err
+	}
+	return result, encodings[i], err
+}
+
+// VerifyMessageHexSignature is like VerifyMessageSignature but accepts a hex encoded signature
+func VerifyMessageHexSignature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, msg []byte, signature string, policy MessagePolicy, opts ...VerifyOption) (bool, MessageEncoding, error) {
+	sig, err := HexDecode(signature)
+	if err != nil {
+		return false, "", err
+	}
+	return VerifyMessageSignature(ctx, caller, address, msg, sig, policy, opts...)
+}
@@ -0,0 +1,121 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageEncodingInterpret(t *testing.T) {
+	tests := []struct {
+		encoding MessageEncoding
+		msg      string
+		want     string
+		wantOK   bool
+	}{
+		{MessageRaw, "0x6869", "0x6869", true},
+		{MessageHexDecoded, "0x6869", "hi", true},
+		{MessageHexDecoded, "0x686", "", false},
+		{MessageHexDecoded, "hi", "", false},
+		{MessageHexString, "hi", "0x6869", true},
+		{MessageLF, "a\r\nb", "a\nb", true},
+		{MessageLF, "a\nb", "", false},
+		{MessageCRLF, "a\nb\r\nc", "a\r\nb\r\nc", true},
+		{MessageCRLF, "a\r\nb", "", false},
+		{MessageCRLF, "ab", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.encoding)+"/"+tt.msg, func(t *testing.T) {
+			got, ok, err := tt.encoding.Interpret([]byte(tt.msg))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.want, string(got))
+			}
+		})
+	}
+	_, _, err := MessageEncoding("base64").Interpret([]byte("hi"))
+	assert.Error(t, err)
+}
+
+func TestVerifyMessageSignature(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	hexMessage := []byte("0x48656c6c6f")
+	text := []byte("Sign in\r\nNonce: 1")
+	tests := []struct {
+		name         string
+		address      common.Address
+		msg          []byte
+		signed       []byte
+		policy       MessagePolicy
+		want         bool
+		wantEncoding MessageEncoding
+		wantErr      bool
+	}{
+		{"raw", wallets.eoa, hexMessage, hexMessage, StrictMessagePolicy, true, MessageRaw, false},
+		{"hex decoded", wallets.eoa, hexMessage, []byte("Hello"), HexMessagePolicy, true, MessageHexDecoded, false},
+		{"hex decoded/strict", wallets.eoa, hexMessage, []byte("Hello"), StrictMessagePolicy, false, "", true},
+		{"hex string", wallets.eoa, []byte("Hello"), hexMessage, HexMessagePolicy, true, MessageHexString, false},
+		{"lf", wallets.eoa, text, []byte("Sign in\nNonce: 1"), LenientMessagePolicy, true, MessageLF, false},
+		{"lf/not in policy", wallets.eoa, text, []byte("Sign in\nNonce: 1"), HexMessagePolicy, false, "", true},
+		{"crlf", wallets.eoa, []byte("Sign in\nNonce: 1"), text, LenientMessagePolicy, true, MessageCRLF, false},
+		{"erc1271/hex decoded", wallets.owner, hexMessage, []byte("Hello"), HexMessagePolicy, true, MessageHexDecoded, false},
+		{"erc1271/wrong magic", wallets.wrongMagic, hexMessage, []byte("Hello"), LenientMessagePolicy, false, "", false},
+		{"empty policy", wallets.eoa, hexMessage, hexMessage, MessagePolicy{}, false, "", true},
+		{"unknown encoding", wallets.eoa, hexMessage, hexMessage, MessagePolicy{"base64"}, false, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signature := wallets.sign(t, tt.signed)
+			result, encoding, err := VerifyMessageSignatureDetailed(context.Background(), wallets.backend, tt.address, tt.msg, signature, tt.policy)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, result.Valid)
+			assert.Equal(t, tt.wantEncoding, encoding)
+			if tt.want {
+				assert.Equal(t, common.BytesToHash(accounts.TextHash(tt.signed)), result.Digest)
+			}
+
+			valid, encoding, _ := VerifyMessageHexSignature(context.Background(), wallets.backend, tt.address, tt.msg, hexutil.Encode(signature), tt.policy)
+			assert.Equal(t, tt.want, valid)
+			assert.Equal(t, tt.wantEncoding, encoding)
+		})
+	}
+}
+
+func TestVerifyMessageSignatureCalls(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	text := []byte("Sign in\r\nNonce: 1")
+	signature := wallets.sign(t, []byte("other"))
+
+	// the elliptic curve is verified once per interpretation, and the contract is only probed until it has no code
+	caller := &countingCaller{ContractCaller: wallets.backend}
+	result, encoding, err := VerifyMessageSignatureDetailed(context.Background(), caller, wallets.eoa, text, signature, LenientMessagePolicy)
+	assert.ErrorIs(t, err, ErrNoContractCode)
+	assert.False(t, result.Valid)
+	assert.Equal(t, MessageEncoding(""), encoding)
+	assert.Equal(t, 1, caller.callContract)
+	assert.Equal(t, 1, caller.codeAt)
+	assert.Len(t, result.Failures, 2)
+}
@@ -0,0 +1,280 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/storyicon/sigverify/contracts/erc1271"
+	"github.com/storyicon/sigverify/contracts/multicall3"
+)
+
+// Multicall3Address is the address that Multicall3 is deployed to on most chains
+var Multicall3Address = ethcommon.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// defaultMulticallBatchSize is the default number of signatures verified by one aggregate3 call
+const defaultMulticallBatchSize = 100
+
+// ERC1271BatchRequest is a signature over a 32-byte digest to be verified by VerifyERC1271Batch
+type ERC1271BatchRequest struct {
+	Address   ethcommon.Address
+	Hash      [32]byte
+	Signature []byte
+}
+
+// ERC1271BatchResponse is the outcome of an ERC1271BatchRequest,
+// Result and Err are the same as the ones returned by VerifyERC1271HashDetailed
+type ERC1271BatchResponse struct {
+	Result *VerificationResult
+	Err    error
+}
+
+// VerifyERC1271Batch verifies many ERC1271 signatures with as few eth_call as possible.
+// The requests are split into chunks of WithMulticallBatchSize, and every chunk is sent to aggregate3 of
+// Multicall3 with allowFailure set, so that one failing wallet does not fail the others.
+// A chunk whose aggregate3 call runs out of gas or reverts is split in half and retried, the chunks that fail
+// with any other error are verified individually.
+// The requests that cannot be settled by the batch, such as reverted calls, wallets that only implement
+// the legacy isValidSignature and ERC6492 signatures, are verified individually like VerifyERC1271HashDetailed,
+// and so are all the requests when Multicall3 is not deployed on the chain.
+// The responses are in the order of requests, the returned error is only set when no call can be made at all.
+// Once ctx is done, the requests that are not settled yet get ctx.Err() as their Err.
+func VerifyERC1271Batch(ctx context.Context, caller bind.ContractCaller, requests []ERC1271BatchRequest, opts ...VerifyOption) ([]ERC1271BatchResponse, error) {
+	options := newVerifyOptions(opts)
+	pinned, err := pinContractCaller(caller, options.block)
+	if err != nil {
+		return nil, err
+	}
+	b := &erc1271Batch{
+		ctx:       ctx,
+		caller:    caller,
+		pinned:    pinned,
+		options:   options,
+		requests:  requests,
+		responses: make([]ERC1271BatchResponse, len(requests)),
+	}
+	for start := 0; start < len(requests); start += options.multicallBatchSize {
+		end := start + options.multicallBatchSize
+		if end > len(requests) {
+			end = len(requests)
+		}
+		indexes := make([]int, 0, end-start)
+		for i := start; i < end; i++ {
+			indexes = append(indexes, i)
+		}
+		b.verify(indexes)
+	}
+	return b.responses, nil
+}
+
+type erc1271Batch struct {
+	ctx       context.Context
+	caller    bind.ContractCaller
+	pinned    bind.ContractCaller
+	options   *verifyOptions
+	requests  []ERC1271BatchRequest
+	responses []ERC1271BatchResponse
+	// noMulticall is set once Multicall3 turns out to be missing
+	noMulticall bool
+}
+
+// verify settles the requests of indexes through aggregate3, and verifies the rest individually
+func (b *erc1271Batch) verify(indexes []int) {
+	if err := b.ctx.Err(); err != nil {
+		b.cancel(indexes, err)
+		return
+	}
+	if b.noMulticall || len(b.options.erc1271Methods) == 0 {
+		for _, i := range indexes {
+			b.verifyIndividually(i)
+		}
+		return
+	}
+	var (
+		method  = b.options.erc1271Methods[0]
+		batched []int
+		calls   []multicall3.Multicall3Call3
+	)
+	for _, i := range indexes {
+		request := b.requests[i]
+		if IsERC6492Signature(request.Signature) {
+			b.verifyIndividually(i)
+			continue
+		}
+		calldata, err := packIsValidSignature(method, request.Hash, request.Signature)
+		if err != nil {
+			b.verifyIndividually(i)
+			continue
+		}
+		batched = append(batched, i)
+		calls = append(calls, multicall3.Multicall3Call3{Target: request.Address, AllowFailure: true, CallData: calldata})
+	}
+	if len(batched) == 0 {
+		return
+	}
+	results, err := b.aggregate3(calls)
+	if err != nil {
+		if ctxErr := b.ctx.Err(); ctxErr != nil {
+			b.cancel(batched, ctxErr)
+			return
+		}
+		if errors.Is(err, ErrNoContractCode) {
+			b.noMulticall = true
+		}
+		if len(batched) > 1 && isSplittableError(err) {
+			b.verify(batched[:len(batched)/2])
+			b.verify(batched[len(batched)/2:])
+			return
+		}
+		for _, i := range batched {
+			b.verifyIndividually(i)
+		}
+		return
+	}
+	for k, i := range batched {
+		magic, expected, err := unpackIsValidSignature(method, results[k])
+		if err != nil {
+			b.verifyIndividually(i)
+			continue
+		}
+		request := b.requests[i]
+		result := newVerificationResult(request.Address, request.Hash[:])
+		block := b.options.block
+		result.Block = &block
+		b.responses[i] = ERC1271BatchResponse{Result: result}
+		if magic == expected {
+			result.succeed(method)
+			continue
+		}
+		result.fail(method, fmt.Errorf("%w: isValidSignature returned 0x%x", ErrInvalidMagicValue, magic))
+		if len(b.options.erc1271Methods) > 1 {
+			// the wallet answered, so the errors of the remaining methods are only kept in the result
+			rest := *b.options
+			rest.erc1271Methods = rest.erc1271Methods[1:]
+			_ = verifyERC1271Digest(b.ctx, b.caller, result, request.Signature, &rest)
+		}
+	}
+}
+
+// cancel fails the requests of indexes with err, which is the error of the done context
+func (b *erc1271Batch) cancel(indexes []int, err error) {
+	for _, i := range indexes {
+		request := b.requests[i]
+		b.responses[i] = ERC1271BatchResponse{Result: newVerificationResult(request.Address, request.Hash[:]), Err: err}
+	}
+}
+
+// isSplittableError reports whether the aggregate3 call failed because of the size of the chunk,
+// which is when it runs out of gas or reverts, so that smaller chunks may succeed
+func isSplittableError(err error) bool {
+	if errors.Is(wrapCallError(err), ErrExecutionReverted) || errors.Is(err, vm.ErrOutOfGas) {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "out of gas") ||
+		strings.Contains(message, "gas required exceeds") ||
+		strings.Contains(message, "exceeds block gas limit")
+}
+
+func (b *erc1271Batch) verifyIndividually(i int) {
+	request := b.requests[i]
+	result := newVerificationResult(request.Address, request.Hash[:])
+	err := verifyERC1271Digest(b.ctx, b.caller, result, request.Signature, b.options)
+	b.responses[i] = ERC1271BatchResponse{Result: result, Err: err}
+}
+
+// aggregate3 calls aggregate3 of Multicall3, ErrNoContractCode is returned if Multicall3 is not deployed
+func (b *erc1271Batch) aggregate3(calls []multicall3.Multicall3Call3) ([]multicall3.Multicall3Result, error) {
+	multicallABI, err := multicall3.Multicall3MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	input, err := multicallABI.Pack("aggregate3", calls)
+	if err != nil {
+		return nil, err
+	}
+	address := b.options.multicallAddress
+	output, err := b.pinned.CallContract(b.ctx, ethereum.CallMsg{To: &address, Gas: b.options.multicallGas, Data: input}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(output) == 0 {
+		if code, err := b.pinned.CodeAt(b.ctx, address, nil); err != nil {
+			return nil, err
+		} else if len(code) == 0 {
+			return nil, ErrNoContractCode
+		}
+	}
+	values, err := multicallABI.Unpack("aggregate3", output)
+	if err != nil {
+		return nil, err
+	}
+	results := *abi.ConvertType(values[0], new([]multicall3.Multicall3Result)).(*[]multicall3.Multicall3Result)
+	if len(results) != len(calls) {
+		return nil, fmt.Errorf("aggregate3 returned %d results for %d calls", len(results), len(calls))
+	}
+	return results, nil
+}
+
+// packIsValidSignature returns the calldata of the isValidSignature variant of the method
+func packIsValidSignature(method VerificationMethod, digest [32]byte, signature []byte) ([]byte, error) {
+	switch method {
+	case MethodERC1271:
+		contractABI, err := erc1271.Erc1271MetaData.GetAbi()
+		if err != nil {
+			return nil, err
+		}
+		return contractABI.Pack("isValidSignature", digest, signature)
+	case MethodERC1271Legacy:
+		contractABI, err := erc1271.Erc1271LegacyMetaData.GetAbi()
+		if err != nil {
+			return nil, err
+		}
+		return contractABI.Pack("isValidSignature", digest[:], signature)
+	default:
+		return nil, fmt.Errorf("unsupported ERC1271 method %q", method)
+	}
+}
+
+// unpackIsValidSignature returns the value returned by a successful call of the isValidSignature variant
+// of the method together with the magic value expected by the variant
+func unpackIsValidSignature(method VerificationMethod, result multicall3.Multicall3Result) ([4]byte, [4]byte, error) {
+	var expected [4]byte
+	switch method {
+	case MethodERC1271:
+		expected = GetERC1271Magic()
+	case MethodERC1271Legacy:
+		expected = GetERC1271LegacyMagic()
+	default:
+		return [4]byte{}, expected, fmt.Errorf("unsupported ERC1271 method %q", method)
+	}
+	if !result.Success {
+		return [4]byte{}, expected, ErrExecutionReverted
+	}
+	if len(result.ReturnData) < 32 {
+		return [4]byte{}, expected, fmt.Errorf("isValidSignature returned %d bytes", len(result.ReturnData))
+	}
+	var magic [4]byte
+	copy(magic[:], result.ReturnData)
+	return magic, expected, nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/storyicon/sigverify/contracts/multicall3"
+	"github.com/stretchr/testify/assert"
+)
+
+// multicallBackend emulates Multicall3 at Multicall3Address on top of the simulated backend.
+// aggregate3 is only emulated in Go by calling the targets one by one, the repository ships no Multicall3
+// bytecode (contracts/multicall3/Multicall3.bin is empty), so the real contract is not exercised here.
+type multicallBackend struct {
+	*backends.SimulatedBackend
+	// maxCalls makes aggregate3 fail like running out of gas when it receives more calls, 0 means no limit
+	maxCalls int
+	// err makes aggregate3 fail with it
+	err error
+	// aggregated counts the aggregate3 calls
+	aggregated int
+}
+
+func (m *multicallBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	if contract == Multicall3Address {
+		return []byte{0xfe}, nil
+	}
+	return m.SimulatedBackend.CodeAt(ctx, contract, blockNumber)
+}
+
+func (m *multicallBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if call.To == nil || *call.To != Multicall3Address {
+		return m.SimulatedBackend.CallContract(ctx, call, blockNumber)
+	}
+	m.aggregated++
+	if m.err != nil {
+		return nil, m.err
+	}
+	multicallABI, err := multicall3.Multicall3MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	values, err := multicallABI.Methods["aggregate3"].Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	calls := *abi.ConvertType(values[0], new([]multicall3.Multicall3Call3)).(*[]multicall3.Multicall3Call3)
+	if m.maxCalls > 0 && len(calls) > m.maxCalls {
+		return nil, errors.New("out of gas")
+	}
+	results := make([]multicall3.Multicall3Result, len(calls))
+	for i, c := range calls {
+		target := c.Target
+		output, err := m.SimulatedBackend.CallContract(ctx, ethereum.CallMsg{To: &target, Data: c.CallData}, blockNumber)
+		results[i] = multicall3.Multicall3Result{Success: err == nil, ReturnData: output}
+		var dataErr rpc.DataError
+		if errors.As(err, &dataErr) {
+			results[i].ReturnData, _ = HexDecode(dataErr.ErrorData().(string))
+		}
+	}
+	return multicallABI.Methods["aggregate3"].Outputs.Pack(results)
+}
+
+func TestVerifyERC1271Batch(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	hash := crypto.Keccak256Hash([]byte("order"))
+	signature := wallets.signHash(t, hash[:])
+	counterfactual, calldata := wallets.counterfactual(t, wallets.eoa)
+	wrapped, err := WrapERC6492Signature(wallets.factory, calldata, signature)
+	assert.NoError(t, err)
+	// the legacy wallet receives the hash as _data and signs over the personal message of its hash
+	legacySignature := wallets.sign(t, crypto.Keccak256(hash[:]))
+
+	tests := []struct {
+		request    ERC1271BatchRequest
+		want       bool
+		wantMethod VerificationMethod
+		wantErr    error
+	}{
+		{
+			request:    ERC1271BatchRequest{Address: wallets.owner, Hash: hash, Signature: signature},
+			want:       true,
+			wantMethod: MethodERC1271,
+		},
+		{
+			request: ERC1271BatchRequest{Address: wallets.owner, Hash: crypto.Keccak256Hash([]byte("other")), Signature: signature},
+		},
+		{
+			request:    ERC1271BatchRequest{Address: wallets.legacy, Hash: hash, Signature: legacySignature},
+			want:       true,
+			wantMethod: MethodERC1271Legacy,
+		},
+		{
+			request: ERC1271BatchRequest{Address: wallets.reverting, Hash: hash, Signature: signature},
+			wantErr: ErrExecutionReverted,
+		},
+		{
+			request: ERC1271BatchRequest{Address: wallets.eoa, Hash: hash, Signature: signature},
+			wantErr: ErrNoContractCode,
+		},
+		{
+			request:    ERC1271BatchRequest{Address: counterfactual, Hash: hash, Signature: wrapped},
+			want:       true,
+			wantMethod: MethodERC6492,
+		},
+		{
+			request:    ERC1271BatchRequest{Address: wallets.alwaysOK, Hash: hash, Signature: []byte{0x00}},
+			want:       true,
+			wantMethod: MethodERC1271,
+		},
+	}
+	requests := make([]ERC1271BatchRequest, 0, len(tests))
+	for _, tt := range tests {
+		requests = append(requests, tt.request)
+	}
+
+	cases := []struct {
+		name           string
+		caller         bind.ContractCaller
+		opts           []VerifyOption
+		wantAggregated int
+	}{
+		{
+			name:           "multicall",
+			caller:         &multicallBackend{SimulatedBackend: wallets.backend},
+			wantAggregated: 1,
+		},
+		{
+			name:           "multicall/chunked",
+			caller:         &multicallBackend{SimulatedBackend: wallets.backend},
+			opts:           []VerifyOption{WithMulticallBatchSize(3)},
+			wantAggregated: 3,
+		},
+		{
+			name:           "multicall/split when out of gas",
+			caller:         &multicallBackend{SimulatedBackend: wallets.backend, maxCalls: 2},
+			wantAggregated: 7,
+		},
+		{
+			name:           "multicall/not split on other errors",
+			caller:         &multicallBackend{SimulatedBackend: wallets.backend, err: errors.New("connection refused")},
+			wantAggregated: 1,
+		},
+		{
+			name:   "without multicall",
+			caller: wallets.backend,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			responses, err := VerifyERC1271Batch(context.Background(), c.caller, requests, c.opts...)
+			assert.NoError(t, err)
+			assert.Len(t, responses, len(tests))
+			for i, tt := range tests {
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, responses[i].Err, tt.wantErr, "request %d", i)
+				} else {
+					assert.NoError(t, responses[i].Err, "request %d", i)
+				}
+				assert.Equal(t, tt.want, responses[i].Result.Valid, "request %d", i)
+				assert.Equal(t, tt.wantMethod, responses[i].Result.Method, "request %d", i)
+				assert.Equal(t, tt.request.Address, responses[i].Result.Address, "request %d", i)
+				assert.Equal(t, common.Hash(tt.request.Hash), responses[i].Result.Digest, "request %d", i)
+			}
+			if backend, ok := c.caller.(*multicallBackend); ok {
+				assert.Equal(t, c.wantAggregated, backend.aggregated)
+			}
+		})
+	}
+}
+
+func TestVerifyERC1271BatchCanceled(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	hash := crypto.Keccak256Hash([]byte("order"))
+	requests := []ERC1271BatchRequest{
+		{Address: wallets.owner, Hash: hash, Signature: wallets.signHash(t, hash[:])},
+		{Address: wallets.alwaysOK, Hash: hash, Signature: []byte{0x00}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	backend := &multicallBackend{SimulatedBackend: wallets.backend}
+	responses, err := VerifyERC1271Batch(ctx, backend, requests)
+	assert.NoError(t, err)
+	assert.Len(t, responses, len(requests))
+	for i, response := range responses {
+		assert.ErrorIs(t, response.Err, context.Canceled, "request %d", i)
+		assert.False(t, response.Result.Valid, "request %d", i)
+		assert.Equal(t, requests[i].Address, response.Result.Address, "request %d", i)
+	}
+	assert.Equal(t, 0, backend.aggregated)
+}
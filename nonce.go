@@ -0,0 +1,302 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+var (
+	// ErrNonceNotFound is returned when a nonce was not generated by the NonceStore or has already been consumed
+	ErrNonceNotFound = errors.New("nonce is unknown or has already been used")
+
+	// ErrNonceExpired is returned when a nonce is consumed after its ttl
+	ErrNonceExpired = errors.New("nonce has expired")
+)
+
+// nonceDigits is the number of decimal digits of a generated nonce, which is about 106 bits of randomness
+const nonceDigits = 32
+
+// NonceStore issues nonces and consumes each of them at most once, so that a signed message
+// carrying a nonce cannot be replayed. It must be safe for concurrent use.
+type NonceStore interface {
+	// Generate issues a new nonce that can be consumed within ttl, 0 means it never expires
+	Generate(ctx context.Context, ttl time.Duration) (string, error)
+	// Consume atomically marks nonce as used, it returns ErrNonceNotFound if nonce was not issued
+	// or has already been consumed, and ErrNonceExpired if its ttl has passed
+	Consume(ctx context.Context, nonce string) error
+}
+
+// generateNonce returns a random decimal nonce without leading zeros, so that the same string
+// is a valid EIP-4361 nonce and the decimal form of a uint256 field of typed data
+func generateNonce() (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(nonceDigits), nil)
+	min := new(big.Int).Exp(big.NewInt(10), big.NewInt(nonceDigits-1), nil)
+	n, err := rand.Int(rand.Reader, new(big.Int).Sub(max, min))
+	if err != nil {
+		return "", err
+	}
+	return n.Add(n, min).String(), nil
+}
+
+// nonceEntries maps the unconsumed nonces to their expiry time, the zero time means no expiry
+type nonceEntries map[string]time.Time
+
+func (e nonceEntries) generate(now time.Time, ttl time.Duration) (string, error) {
+	e.prune(now)
+	nonce, err := generateNonce()
+	if err != nil {
+		return "", err
+	}
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = now.Add(ttl)
+	}
+	e[nonce] = expiresAt
+	return nonce, nil
+}
+
+func (e nonceEntries) consume(now time.Time, nonce string) error {
+	expiresAt, ok := e[nonce]
+	if !ok {
+		return ErrNonceNotFound
+	}
+	delete(e, nonce)
+	if !expiresAt.IsZero() && !now.Before(expiresAt) {
+		return ErrNonceExpired
+	}
+	return nil
+}
+
+func (e nonceEntries) prune(now time.Time) {
+	for nonce, expiresAt := range e {
+		if !expiresAt.IsZero() && !now.Before(expiresAt) {
+			delete(e, nonce)
+		}
+	}
+}
+
+// MemoryNonceStore is an in-memory NonceStore, its nonces are lost when the process exits
+type MemoryNonceStore struct {
+	mu      sync.Mutex
+	entries nonceEntries
+	now     func() time.Time
+}
+
+// NewMemoryNonceStore creates an in-memory NonceStore
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{
+		entries: make(nonceEntries),
+		now:     time.Now,
+	}
+}
+
+// Generate implements NonceStore
+func (s *MemoryNonceStore) Generate(ctx context.Context, ttl time.Duration) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.entries.generate(s.now(), ttl)
+}
+
+// Consume implements NonceStore
+func (s *MemoryNonceStore) Consume(ctx context.Context, nonce string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.entries.consume(s.now(), nonce)
+}
+
+// FileNonceStore is a NonceStore that keeps the unconsumed nonces in a JSON file, so that they survive restarts.
+// Every operation reads and atomically replaces the file, the file must not be shared by several processes.
+type FileNonceStore struct {
+	path string
+	mu   sync.Mutex
+	now  func() time.Time
+}
+
+// NewFileNonceStore creates a NonceStore backed by the file at path, which is created if it does not exist
+func NewFileNonceStore(path string) (*FileNonceStore, error) {
+	s := &FileNonceStore{
+		path: path,
+		now:  time.Now,
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if err := s.save(nonceEntries{}); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Generate implements NonceStore
+func (s *FileNonceStore) Generate(ctx context.Context, ttl time.Duration) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := entries.generate(s.now(), ttl)
+	if err != nil {
+		return "", err
+	}
+	return nonce, s.save(entries)
+}
+
+// Consume implements NonceStore
+func (s *FileNonceStore) Consume(ctx context.Context, nonce string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	consumeErr := entries.consume(s.now(), nonce)
+	if errors.Is(consumeErr, ErrNonceNotFound) {
+		return consumeErr
+	}
+	if err := s.save(entries); err != nil {
+		return err
+	}
+	return consumeErr
+}
+
+func (s *FileNonceStore) load() (nonceEntries, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(nonceEntries)
+	if len(data) == 0 {
+		return entries, nil
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid nonce file %s: %w", s.path, err)
+	}
+	return entries, nil
+}
+
+// save writes entries to a temporary file and renames it over the store, so that a crash never leaves a partial file
+func (s *FileNonceStore) save(entries nonceEntries) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// VerifySIWEMessageWithNonce is like VerifySIWEMessage, and consumes the nonce of the message from store once the
+// message and its signature are valid, so that the message cannot be used to sign in again.
+// Consuming the nonce is its check, so WithSIWENonce is optional here, while WithSIWEDomain is still required.
+// A nonce that cannot be consumed is reported as a *SIWEError of SIWEFieldNonce wrapping ErrNonceNotFound or ErrNonceExpired.
+func VerifySIWEMessageWithNonce(ctx context.Context, caller bind.ContractCaller, store NonceStore, message string, signature []byte, opts ...SIWEOption) (*SIWEMessage, error) {
+	m, err := verifySIWEMessage(ctx, caller, message, signature, newSIWEOptions(opts))
+	if err != nil {
+		return m, err
+	}
+	if err := store.Consume(ctx, m.Nonce); err != nil {
+		return m, &SIWEError{Field: SIWEFieldNonce, Err: err}
+	}
+	return m, nil
+}
+
+// VerifyTypedDataSignatureWithNonce is like VerifyTypedDataSignatureWithFallback, and consumes the nonce found in the
+// nonceField of data.Message from store once the signature is valid, so that the typed data cannot be replayed.
+// The field may be a string or a number, numbers are compared by their decimal form.
+// The nonces of NonceStore.Generate do not fit in a float64, so typed data decoded by encoding/json must be decoded
+// with json.Decoder.UseNumber, a float64 nonce above 2^53 is rejected since it may have been rounded.
+// An invalid signature is reported as false without consuming the nonce.
+func VerifyTypedDataSignatureWithNonce(ctx context.Context, caller bind.ContractCaller, store NonceStore, address ethcommon.Address, data apitypes.TypedData, nonceField string, signature []byte, opts ...VerifyOption) (bool, error) {
+	nonce, err := typedDataNonce(data, nonceField)
+	if err != nil {
+		return false, err
+	}
+	valid, err := VerifyTypedDataSignatureWithFallback(ctx, caller, address, data, signature, opts...)
+	if err != nil || !valid {
+		return false, err
+	}
+	if err := store.Consume(ctx, nonce); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// maxExactFloat64 is 2^53, the integers above it can not all be held exactly by a float64
+const maxExactFloat64 = 1 << 53
+
+// typedDataNonce returns the decimal or string form of the nonceField of data.Message
+func typedDataNonce(data apitypes.TypedData, nonceField string) (string, error) {
+	value, ok := data.Message[nonceField]
+	if !ok {
+		return "", fmt.Errorf("typed data has no %q field", nonceField)
+	}
+	switch v := value.(type) {
+	case string:
+		if n, ok := math.ParseBig256(v); ok && Has0xPrefix(v) {
+			return n.String(), nil
+		}
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case float64:
+		if v > maxExactFloat64 || v < -maxExactFloat64 {
+			return "", fmt.Errorf("typed data field %q is too large for a float64, decode it with json.Decoder.UseNumber", nonceField)
+		}
+		n, accuracy := big.NewFloat(v).Int(nil)
+		if accuracy != big.Exact {
+			return "", fmt.Errorf("typed data field %q is not an integer", nonceField)
+		}
+		return n.String(), nil
+	case *big.Int:
+		return v.String(), nil
+	case *math.HexOrDecimal256:
+		return (*big.Int)(v).String(), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("typed data field %q has unsupported type %T", nonceField, value)
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNonceStore(t *testing.T) {
+	ctx := context.Background()
+	fileStore, err := NewFileNonceStore(filepath.Join(t.TempDir(), "nonces.json"))
+	assert.NoError(t, err)
+	memoryStore := NewMemoryNonceStore()
+	stores := map[string]struct {
+		store NonceStore
+		now   *func() time.Time
+	}{
+		"memory": {memoryStore, &memoryStore.now},
+		"file":   {fileStore, &fileStore.now},
+	}
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+			*s.now = func() time.Time { return now }
+
+			nonce, err := s.store.Generate(ctx, time.Minute)
+			assert.NoError(t, err)
+			assert.Len(t, nonce, nonceDigits)
+			assert.True(t, isSIWEChars(nonce, siweAlphaDigit))
+			assert.NoError(t, s.store.Consume(ctx, nonce))
+			assert.ErrorIs(t, s.store.Consume(ctx, nonce), ErrNonceNotFound)
+			assert.ErrorIs(t, s.store.Consume(ctx, "12345678"), ErrNonceNotFound)
+
+			expiring, err := s.store.Generate(ctx, time.Minute)
+			assert.NoError(t, err)
+			forever, err := s.store.Generate(ctx, 0)
+			assert.NoError(t, err)
+			now = now.Add(time.Minute)
+			assert.ErrorIs(t, s.store.Consume(ctx, expiring), ErrNonceExpired)
+			assert.ErrorIs(t, s.store.Consume(ctx, expiring), ErrNonceNotFound)
+			assert.NoError(t, s.store.Consume(ctx, forever))
+
+			concurrent, err := s.store.Generate(ctx, time.Minute)
+			assert.NoError(t, err)
+			var (
+				wg       sync.WaitGroup
+				mu       sync.Mutex
+				consumed int
+			)
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if s.store.Consume(ctx, concurrent) == nil {
+						mu.Lock()
+						consumed++
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+			assert.Equal(t, 1, consumed)
+		})
+	}
+}
+
+func TestFileNonceStorePersistence(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "nonces.json")
+	store, err := NewFileNonceStore(path)
+	assert.NoError(t, err)
+	nonce, err := store.Generate(ctx, time.Hour)
+	assert.NoError(t, err)
+
+	reopened, err := NewFileNonceStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, reopened.Consume(ctx, nonce))
+	assert.ErrorIs(t, store.Consume(ctx, nonce), ErrNonceNotFound)
+
+	assert.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+	_, err = reopened.Generate(ctx, time.Hour)
+	assert.Error(t, err)
+}
+
+func TestVerifySIWEMessageWithNonce(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	ctx := context.Background()
+	store := NewMemoryNonceStore()
+	nonce, err := store.Generate(ctx, time.Hour)
+	assert.NoError(t, err)
+	m := siweMessageFor(wallets.owner)
+	m.Nonce = nonce
+	message := m.String()
+	signature := wallets.sign(t, []byte(message))
+	domain := WithSIWEDomain("example.com")
+
+	_, err = VerifySIWEMessageWithNonce(ctx, wallets.backend, store, message, signature)
+	assertSIWEError(t, err, SIWEFieldDomain, ErrSIWEMissingExpectation)
+
+	_, err = VerifySIWEMessageWithNonce(ctx, wallets.backend, store, message, wallets.sign(t, []byte("other")), domain)
+	assertSIWEError(t, err, SIWEFieldSignature, ErrSIWEInvalidSignature)
+
+	_, err = VerifySIWEMessageWithNonce(ctx, wallets.backend, store, message, signature, domain)
+	assert.NoError(t, err)
+
+	_, err = VerifySIWEMessageWithNonce(ctx, wallets.backend, store, message, signature, domain)
+	assertSIWEError(t, err, SIWEFieldNonce, ErrNonceNotFound)
+}
+
+func TestVerifyTypedDataSignatureWithNonce(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	ctx := context.Background()
+	store := NewMemoryNonceStore()
+	nonce, err := store.Generate(ctx, time.Hour)
+	assert.NoError(t, err)
+	data := ambireTypedData()
+	data.Types["RandomAmbireTypeStruct"] = append(data.Types["RandomAmbireTypeStruct"], apitypes.Type{Name: "nonce", Type: "uint256"})
+	data.Message["nonce"] = nonce
+	_, dataHash, err := HashTypedData(data)
+	assert.NoError(t, err)
+	signature := wallets.signHash(t, dataHash)
+
+	valid, err := VerifyTypedDataSignatureWithNonce(ctx, wallets.backend, store, wallets.owner, data, "nonce", wallets.signHash(t, make([]byte, 32)))
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	valid, err = VerifyTypedDataSignatureWithNonce(ctx, wallets.backend, store, wallets.eoa, data, "nonce", signature)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = VerifyTypedDataSignatureWithNonce(ctx, wallets.backend, store, wallets.eoa, data, "nonce", signature)
+	assert.ErrorIs(t, err, ErrNonceNotFound)
+	assert.False(t, valid)
+
+	_, err = VerifyTypedDataSignatureWithNonce(ctx, wallets.backend, store, wallets.eoa, data, "salt", signature)
+	assert.Error(t, err)
+}
+
+func TestTypedDataNonce(t *testing.T) {
+	n := math.HexOrDecimal256(*big.NewInt(42))
+	for _, value := range []interface{}{"42", "0x2a", json.Number("42"), float64(42), big.NewInt(42), &n, uint64(42)} {
+		nonce, err := typedDataNonce(apitypes.TypedData{Message: apitypes.TypedDataMessage{"nonce": value}}, "nonce")
+		assert.NoError(t, err)
+		assert.Equal(t, "42", nonce, "%T", value)
+	}
+	_, err := typedDataNonce(apitypes.TypedData{Message: apitypes.TypedDataMessage{"nonce": 4.2}}, "nonce")
+	assert.Error(t, err)
+
+	// a generated nonce is rounded when decoded into a float64, but not into a json.Number
+	const generated = `{"nonce": 12345678901234567930781957710571}`
+	var message apitypes.TypedDataMessage
+	assert.NoError(t, json.Unmarshal([]byte(generated), &message))
+	_, err = typedDataNonce(apitypes.TypedData{Message: message}, "nonce")
+	assert.Error(t, err)
+	decoder := json.NewDecoder(strings.NewReader(generated))
+	decoder.UseNumber()
+	assert.NoError(t, decoder.Decode(&message))
+	nonce, err := typedDataNonce(apitypes.TypedData{Message: message}, "nonce")
+	assert.NoError(t, err)
+	assert.Equal(t, "12345678901234567930781957710571", nonce)
+
+	nonce, err = typedDataNonce(apitypes.TypedData{Message: apitypes.TypedDataMessage{"nonce": float64(1 << 53)}}, "nonce")
+	assert.NoError(t, err)
+	assert.Equal(t, "9007199254740992", nonce)
+}
@@ -0,0 +1,222 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// RecoveryOption is used to adjust how a signature is recovered and verified
+type RecoveryOption func(*recoveryOptions)
+
+type recoveryOptions struct {
+	strictLowS bool
+	eip155     bool
+	chainID    *big.Int
+}
+
+func newRecoveryOptions(opts []RecoveryOption) *recoveryOptions {
+	options := &recoveryOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WithStrictLowS enables the strict mode defined by EIP-2, signatures whose s value
+// is greater than secp256k1n/2 are rejected with ErrHighS.
+// Without it, the malleable twin (r, n-s, v^1) of a valid signature is accepted as well.
+// In strict mode the Ex functions also reject the EIP-2098 compact form and the ledger v of 0 or 1 with
+// ErrNonCanonicalSignature, so that a signature has a single accepted encoding, Signature.Bytes() returns that form.
+// EIP-155 style v values are only accepted when WithEIP155 is given with a chain id, in which case
+// a signature has exactly two accepted encodings, v of 27 or 28 and v of chainId*2+35 or chainId*2+36.
+func WithStrictLowS() RecoveryOption {
+	return func(o *recoveryOptions) {
+		o.strictLowS = true
+	}
+}
+
+// WithEIP155 makes RecoveryAddressEx accept EIP-155 style v values (chainId*2+35 or chainId*2+36),
+// which some wallets and transaction signing tools produce for messages as well.
+// v may occupy more than one byte after r || s when the chain id is large.
+// If chainID is not nil, signatures bound to any other chain are rejected with ErrChainIDMismatch.
+func WithEIP155(chainID *big.Int) RecoveryOption {
+	return func(o *recoveryOptions) {
+		o.eip155 = true
+		o.chainID = chainID
+	}
+}
+
+// VerifyOption is used to adjust how signatures are verified by the functions that may call contracts,
+// such as VerifySignatureEx and VerifyERC1271Signature
+type VerifyOption func(*verifyOptions)
+
+type verifyOptions struct {
+	recovery       []RecoveryOption
+	erc1271Methods []VerificationMethod
+	block          BlockReference
+
+	multicallAddress   ethcommon.Address
+	multicallBatchSize int
+	multicallGas       uint64
+
+	errorABIs []abi.ABI
+
+	strategy         VerifyStrategy
+	accountCache     AccountCache
+	delegationPolicy DelegationPolicy
+}
+
+func newVerifyOptions(opts []VerifyOption) *verifyOptions {
+	options := &verifyOptions{
+		erc1271Methods:     []VerificationMethod{MethodERC1271, MethodERC1271Legacy},
+		multicallAddress:   Multicall3Address,
+		multicallBatchSize: defaultMulticallBatchSize,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WithRecoveryOptions applies the given RecoveryOption to the elliptic curve verification
+func WithRecoveryOptions(opts ...RecoveryOption) VerifyOption {
+	return func(o *verifyOptions) {
+		o.recovery = append(o.recovery, opts...)
+	}
+}
+
+// WithERC1271Methods sets which ERC1271 ABIs are probed and in which order, the accepted methods are
+// MethodERC1271 for isValidSignature(bytes32,bytes) and MethodERC1271Legacy for isValidSignature(bytes,bytes).
+// By default MethodERC1271 is probed first and MethodERC1271Legacy second.
+func WithERC1271Methods(methods ...VerificationMethod) VerifyOption {
+	return func(o *verifyOptions) {
+		o.erc1271Methods = methods
+	}
+}
+
+// WithBlockNumber performs the contract calls on the state of the given block instead of the latest one,
+// so that a signature can be verified as of the block it was submitted in.
+func WithBlockNumber(number *big.Int) VerifyOption {
+	return func(o *verifyOptions) {
+		o.block = BlockReference{Number: number}
+	}
+}
+
+// WithBlockHash performs the contract calls on the state of the block with the given hash (EIP-1898),
+// which is not affected by reorgs. The caller must implement BlockHashContractCaller, otherwise
+// ErrNoBlockHashState is returned.
+func WithBlockHash(hash ethcommon.Hash) VerifyOption {
+	return func(o *verifyOptions) {
+		o.block = BlockReference{Hash: hash}
+	}
+}
+
+// WithPendingState performs the contract calls on the pending state. The caller must implement
+// bind.PendingContractCaller, otherwise bind.ErrNoPendingState is returned.
+func WithPendingState() VerifyOption {
+	return func(o *verifyOptions) {
+		o.block = BlockReference{Pending: true}
+	}
+}
+
+// WithMulticall3 sets the address of Multicall3 used by VerifyERC1271Batch, the default is Multicall3Address
+func WithMulticall3(address ethcommon.Address) VerifyOption {
+	return func(o *verifyOptions) {
+		o.multicallAddress = address
+	}
+}
+
+// WithMulticallBatchSize sets the maximum number of signatures verified by one aggregate3 call
+// of VerifyERC1271Batch, the default is 100. Values less than 1 are ignored.
+func WithMulticallBatchSize(size int) VerifyOption {
+	return func(o *verifyOptions) {
+		if size > 0 {
+			o.multicallBatchSize = size
+		}
+	}
+}
+
+// WithMulticallGas sets the gas limit of every aggregate3 call of VerifyERC1271Batch,
+// by default the gas limit is decided by the node. A chunk that runs out of gas is split in half and retried.
+func WithMulticallGas(gas uint64) VerifyOption {
+	return func(o *verifyOptions) {
+		o.multicallGas = gas
+	}
+}
+
+// WithErrorABIs decodes the custom errors of the given ABIs from the revert data of failing contract calls,
+// which are reported by *RevertError. Error(string) and Panic(uint256) are always decoded.
+func WithErrorABIs(abis ...abi.ABI) VerifyOption {
+	return func(o *verifyOptions) {
+		o.errorABIs = append(o.errorABIs, abis...)
+	}
+}
+
+// VerifyStrategy decides which verification methods VerifySignatureEx and its variants try, and in which order
+type VerifyStrategy int
+
+const (
+	// StrategyECDSAFirst tries the elliptic curve verification first, and calls the contract only when it fails.
+	// No code is fetched in advance, which is the default.
+	StrategyECDSAFirst VerifyStrategy = iota
+	// StrategyClassifyAccount fetches the code of the address first, look up ClassifyAccount, and then
+	// only tries the elliptic curve verification for an AccountEOA, only ERC1271 for an AccountContract and
+	// only ERC6492 for an AccountUndeployed, so that no call that is guaranteed to fail is made.
+	// An AccountDelegated is verified according to WithDelegationPolicy.
+	StrategyClassifyAccount
+)
+
+// WithStrategy sets the VerifyStrategy, the default is StrategyECDSAFirst
+func WithStrategy(strategy VerifyStrategy) VerifyOption {
+	return func(o *verifyOptions) {
+		o.strategy = strategy
+	}
+}
+
+// WithAccountCache caches the classification of addresses made by StrategyClassifyAccount and ClassifyAccount
+func WithAccountCache(cache AccountCache) VerifyOption {
+	return func(o *verifyOptions) {
+		o.accountCache = cache
+	}
+}
+
+// DelegationPolicy decides how the signatures of EIP-7702 delegated EOAs are verified. Such an account
+// has both a private key and the code of its delegate, so both the elliptic curve verification and
+// isValidSignature of ERC1271, which runs the code of the delegate, may validate a signature.
+type DelegationPolicy int
+
+const (
+	// DelegationEither accepts a signature validated by either method, the elliptic curve verification is tried first.
+	// It is the default, and it does not need the code of the address unless StrategyClassifyAccount is used.
+	DelegationEither DelegationPolicy = iota
+	// DelegationECDSAOnly only accepts signatures of the private key of a delegated EOA
+	DelegationECDSAOnly
+	// DelegationERC1271Only only accepts signatures validated by the delegate of a delegated EOA,
+	// which is useful when the delegate enforces rules such as session keys or spending limits
+	DelegationERC1271Only
+)
+
+// WithDelegationPolicy sets the DelegationPolicy for EIP-7702 delegated EOAs, the default is DelegationEither.
+// With any other policy, the code of the address is fetched to recognize the delegation designator,
+// and the delegate is reported by VerificationResult.Account.
+func WithDelegationPolicy(policy DelegationPolicy) VerifyOption {
+	return func(o *verifyOptions) {
+		o.delegationPolicy = policy
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"fmt"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// VerificationMethod describes the way a signature was verified
+type VerificationMethod string
+
+const (
+	// MethodECDSA is a standard elliptic curve signature with V 27 or 28
+	MethodECDSA VerificationMethod = "ecdsa"
+	// MethodECDSALedger is an elliptic curve signature with V 0 or 1, which is produced by wallets such as ledger
+	MethodECDSALedger VerificationMethod = "ecdsa-ledger"
+	// MethodECDSACompact is an EIP-2098 compact elliptic curve signature
+	MethodECDSACompact VerificationMethod = "ecdsa-eip2098"
+	// MethodECDSAEIP155 is an elliptic curve signature with an EIP-155 style V, look up WithEIP155
+	MethodECDSAEIP155 VerificationMethod = "ecdsa-eip155"
+	// MethodERC1271 is a smart contract wallet signature validated by isValidSignature(bytes32,bytes)
+	MethodERC1271 VerificationMethod = "erc1271"
+	// MethodERC1271Legacy is a smart contract wallet signature validated by the draft isValidSignature(bytes,bytes)
+	MethodERC1271Legacy VerificationMethod = "erc1271-legacy"
+	// MethodERC6492 is a signature of a counterfactual wallet, which is deployed by its factory and
+	// then validated by isValidSignature(bytes32,bytes) within a single eth_call
+	MethodERC6492 VerificationMethod = "erc6492"
+)
+
+// VerificationFailure records why a verification method did not validate the signature
+type VerificationFailure struct {
+	Method VerificationMethod
+	Err    error
+}
+
+// Error implements the error interface
+func (f VerificationFailure) Error() string {
+	return fmt.Sprintf("%s: %v", f.Method, f.Err)
+}
+
+// Unwrap returns the underlying error
+func (f VerificationFailure) Unwrap() error {
+	return f.Err
+}
+
+// VerificationResult explains how and why a signature was verified
+type VerificationResult struct {
+	// Valid reports whether one of the methods validated the signature
+	Valid bool
+	// Method is the method that validated the signature, it is empty when Valid is false
+	Method VerificationMethod
+	// Address is the address that the signature was checked against
+	Address ethcommon.Address
+	// RecoveredAddress is the signer recovered by elliptic curve verification,
+	// it is the zero address when the recovery was not attempted or failed
+	RecoveredAddress ethcommon.Address
+	// Digest is the 32-byte hash that was checked
+	Digest ethcommon.Hash
+	// Account is the classification of Address, which reports the delegate of an EIP-7702 delegated EOA.
+	// It is nil unless StrategyClassifyAccount or a DelegationPolicy other than DelegationEither is used
+	Account *Account
+	// Block is the block whose state the contract calls were performed on, it is nil when no contract was called.
+	// The latest block is recorded unresolved as the zero BlockReference, which is "latest", so that verifying
+	// does not cost another RPC, use WithBlockNumber or WithBlockHash to record a concrete block.
+	Block *BlockReference
+	// Failures lists, in the order they were tried, the methods that did not validate the signature
+	Failures []VerificationFailure
+
+	// message is the original message of a text signature, which the legacy isValidSignature(bytes,bytes)
+	// receives as _data instead of Digest, it is nil on the hash and typed data paths
+	message []byte
+}
+
+func newVerificationResult(address ethcommon.Address, digest []byte) *VerificationResult {
+	return &VerificationResult{
+		Address: address,
+		Digest:  ethcommon.BytesToHash(digest),
+	}
+}
+
+func (r *VerificationResult) fail(method VerificationMethod, err error) {
+	r.Failures = append(r.Failures, VerificationFailure{Method: method, Err: err})
+}
+
+func (r *VerificationResult) succeed(method VerificationMethod) {
+	r.Valid = true
+	r.Method = method
+}
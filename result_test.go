@@ -0,0 +1,90 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyEllipticCurveSignatureExDetailed(t *testing.T) {
+	tests := []struct {
+		name       string
+		address    string
+		data       []byte
+		signature  string
+		wantValid  bool
+		wantMethod VerificationMethod
+		wantErr    bool
+	}{
+		{
+			name:       "standard",
+			address:    "0xb052C02346F80cF6ae4DF52c10FABD3e0aD24d81",
+			data:       []byte("hello"),
+			signature:  "0x0498c6564863c78e663848b963fde1ea1d860d5d882d2abdb707d1e9179ff80630a4a71705da534a562c08cb64a546c6132de26eb77a44f086832cbc1dbe01f71b",
+			wantValid:  true,
+			wantMethod: MethodECDSA,
+		},
+		{
+			name:       "ledger",
+			address:    "0x545087bd36c7F0eFaeC26252Ee62085CA9A726AC",
+			data:       []byte("abc"),
+			signature:  "0xb6a1ef0b63715a4d303e3935e4a6c75c89ead4311c089e98082e7eaf7e4b460a19e998df7c9ad308e4e5db376364b9e5e4b6f75c4628452cedd13641d1099c8e00",
+			wantValid:  true,
+			wantMethod: MethodECDSALedger,
+		},
+		{
+			name:       "compact",
+			address:    "0x2e988A386a799F506693793c6A5AF6B54dfAaBfB",
+			data:       []byte("Hello World"),
+			signature:  "0x68a020a209d3d56c46f38cc50a33f704f4a9a10a59377f8dd762ac66910e9b907e865ad05c4035ab5792787d4a0297a43617ae897930a6fe4d822b8faea52064",
+			wantValid:  true,
+			wantMethod: MethodECDSACompact,
+		},
+		{
+			name:      "address mismatch",
+			address:   "0x545087bd36c7F0eFaeC26252Ee62085CA9A726AC",
+			data:      []byte("hello"),
+			signature: "0x0498c6564863c78e663848b963fde1ea1d860d5d882d2abdb707d1e9179ff80630a4a71705da534a562c08cb64a546c6132de26eb77a44f086832cbc1dbe01f71b",
+		},
+		{
+			name:      "bad length",
+			address:   "0xb052C02346F80cF6ae4DF52c10FABD3e0aD24d81",
+			data:      []byte("hello"),
+			signature: "0x0498c6",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := VerifyEllipticCurveSignatureExDetailed(common.HexToAddress(tt.address), tt.data, MustMustHexDecode(t, tt.signature))
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantValid, result.Valid)
+			assert.Equal(t, tt.wantMethod, result.Method)
+			if tt.wantValid {
+				assert.Empty(t, result.Failures)
+				assert.Equal(t, common.HexToAddress(tt.address), result.RecoveredAddress)
+			} else {
+				assert.Len(t, result.Failures, 1)
+			}
+		})
+	}
+}
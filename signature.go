@@ -0,0 +1,296 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signature is a parsed secp256k1 signature.
+// V is always kept in the yellow paper form, which is 27 or 28.
+type Signature struct {
+	R [32]byte
+	S [32]byte
+	V byte
+}
+
+// ParseSignature parses a 65-byte r || s || v signature, where v may be 0/1 or 27/28,
+// or a 64-byte EIP-2098 compact signature.
+func ParseSignature(sig []byte) (Signature, error) {
+	if len(sig) == CompactSignatureLength {
+		expanded, err := ExpandCompactSignature(sig)
+		if err != nil {
+			return Signature{}, err
+		}
+		sig = expanded
+	}
+	if len(sig) != crypto.SignatureLength {
+		return Signature{}, fmt.Errorf("%w: signature must be %d or %d bytes long", ErrInvalidSignatureLength, crypto.SignatureLength, CompactSignatureLength)
+	}
+	var signature Signature
+	copy(signature.R[:], sig[:32])
+	copy(signature.S[:], sig[32:64])
+	if err := signature.setV(new(big.Int).SetUint64(uint64(sig[crypto.RecoveryIDOffset]))); err != nil {
+		return Signature{}, err
+	}
+	return signature, nil
+}
+
+// ParseHexSignature parses a hex encoded signature, the string may be prefixed with "0x".
+func ParseHexSignature(s string) (Signature, error) {
+	sig, err := HexDecode(s)
+	if err != nil {
+		return Signature{}, err
+	}
+	return ParseSignature(sig)
+}
+
+// ParseBase64Signature parses a base64 encoded signature.
+func ParseBase64Signature(s string) (Signature, error) {
+	sig, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return Signature{}, err
+	}
+	return ParseSignature(sig)
+}
+
+// ParseJSONSignature parses a JSON encoded signature, look up Signature.UnmarshalJSON for the accepted forms.
+func ParseJSONSignature(data []byte) (Signature, error) {
+	var signature Signature
+	if err := json.Unmarshal(data, &signature); err != nil {
+		return Signature{}, err
+	}
+	return signature, nil
+}
+
+// YParity returns the recovery id of the signature, which is 0 or 1.
+// A V of 0 or 1 set by hand, such as the unset V of the zero value, is taken as the recovery id itself.
+// Any other V than 0, 1, 27 or 28 is invalid and returned as it is, so that it is not mistaken for a recovery id.
+func (s Signature) YParity() byte {
+	switch s.V {
+	case 27, 28:
+		return s.V - 27
+	default:
+		return s.V
+	}
+}
+
+// v returns the yellow paper form of V, which is 27 or 28, an invalid V is returned as it is
+func (s Signature) v() byte {
+	if s.V == 0 || s.V == 1 {
+		return s.V + 27
+	}
+	return s.V
+}
+
+// IsLowS reports whether s is in the lower half of the curve order, look up IsLowS for more comments.
+func (s Signature) IsLowS() bool {
+	return IsLowS(s.Bytes())
+}
+
+// Normalize returns the canonical low-s form of the signature, look up NormalizeSignature for more comments.
+func (s Signature) Normalize() Signature {
+	normalized, _ := NormalizeSignature(s.Bytes())
+	signature, _ := ParseSignature(normalized)
+	return signature
+}
+
+// Bytes returns the 65-byte r || s || v encoding of the signature, v is 27 or 28, a V of 0 or 1 set by hand
+// is converted. An invalid V is kept as it is, so that the recovery rejects it instead of verifying another signature.
+// It is the canonical encoding accepted under WithStrictLowS, the one to deduplicate signatures on.
+func (s Signature) Bytes() []byte {
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig[:32], s.R[:])
+	copy(sig[32:64], s.S[:])
+	sig[crypto.RecoveryIDOffset] = s.v()
+	return sig
+}
+
+// Compact returns the 64-byte EIP-2098 encoding of the signature, a high-s signature is normalized first.
+func (s Signature) Compact() []byte {
+	compact, _ := CompactSignature(s.Bytes())
+	return compact
+}
+
+// Hex returns the 0x prefixed hex encoding of Bytes.
+func (s Signature) Hex() string {
+	return hexutil.Encode(s.Bytes())
+}
+
+// Base64 returns the base64 encoding of Bytes.
+func (s Signature) Base64() string {
+	return base64.StdEncoding.EncodeToString(s.Bytes())
+}
+
+// String implements fmt.Stringer.
+func (s Signature) String() string {
+	return s.Hex()
+}
+
+type jsonSignature struct {
+	R             string          `json:"r"`
+	S             string          `json:"s"`
+	V             json.RawMessage `json:"v,omitempty"`
+	YParity       json.RawMessage `json:"yParity,omitempty"`
+	RecoveryParam json.RawMessage `json:"recoveryParam,omitempty"`
+	VS            string          `json:"_vs,omitempty"`
+	YParityAndS   string          `json:"yParityAndS,omitempty"`
+}
+
+// MarshalJSON encodes the signature as {"r": "0x..", "s": "0x..", "v": 27, "yParity": 0}.
+func (s Signature) MarshalJSON() ([]byte, error) {
+	if s.YParity() > 1 {
+		return nil, fmt.Errorf("%w (V is not 0, 1, 27 or 28)", ErrInvalidRecoveryID)
+	}
+	return json.Marshal(struct {
+		R       string `json:"r"`
+		S       string `json:"s"`
+		V       byte   `json:"v"`
+		YParity byte   `json:"yParity"`
+	}{
+		R:       hexutil.Encode(s.R[:]),
+		S:       hexutil.Encode(s.S[:]),
+		V:       s.v(),
+		YParity: s.YParity(),
+	})
+}
+
+// UnmarshalJSON decodes a signature from a hex or base64 string, or from an object as returned by
+// ethers.js splitSignature. In the object form r is required, together with either s and one of v,
+// yParity and recoveryParam, or the EIP-2098 _vs/yParityAndS value. Numbers may be JSON numbers or strings.
+func (s *Signature) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		signature, err := ParseHexSignature(str)
+		if err != nil {
+			if signature, err = ParseBase64Signature(str); err != nil {
+				return fmt.Errorf("signature is neither hex nor base64 encoded")
+			}
+		}
+		*s = signature
+		return nil
+	}
+	var raw jsonSignature
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	r, err := decodeJSONWord("r", raw.R)
+	if err != nil {
+		return err
+	}
+	vs := raw.VS
+	if vs == "" {
+		vs = raw.YParityAndS
+	}
+	hasV := len(raw.V) > 0 || len(raw.YParity) > 0 || len(raw.RecoveryParam) > 0
+	if vs != "" && (raw.S == "" || !hasV) {
+		word, err := decodeJSONWord("_vs", vs)
+		if err != nil {
+			return err
+		}
+		signature, err := ParseSignature(append(r[:], word[:]...))
+		if err != nil {
+			return err
+		}
+		*s = signature
+		return nil
+	}
+	signature := Signature{R: r}
+	if signature.S, err = decodeJSONWord("s", raw.S); err != nil {
+		return err
+	}
+	var v *big.Int
+	switch {
+	case len(raw.V) > 0:
+		v, err = decodeJSONNumber(raw.V)
+	case len(raw.YParity) > 0:
+		v, err = decodeJSONNumber(raw.YParity)
+	case len(raw.RecoveryParam) > 0:
+		v, err = decodeJSONNumber(raw.RecoveryParam)
+	default:
+		err = fmt.Errorf("%w: signature is missing v", ErrInvalidRecoveryID)
+	}
+	if err != nil {
+		return err
+	}
+	if err := signature.setV(v); err != nil {
+		return err
+	}
+	*s = signature
+	return nil
+}
+
+func (s *Signature) setV(v *big.Int) error {
+	if !v.IsUint64() {
+		return fmt.Errorf("%w (V is not 0, 1, 27 or 28)", ErrInvalidRecoveryID)
+	}
+	switch v.Uint64() {
+	case 0, 1:
+		s.V = byte(v.Uint64()) + 27
+	case 27, 28:
+		s.V = byte(v.Uint64())
+	default:
+		return fmt.Errorf("%w (V is not 0, 1, 27 or 28)", ErrInvalidRecoveryID)
+	}
+	return nil
+}
+
+func decodeJSONWord(name string, s string) ([32]byte, error) {
+	var word [32]byte
+	if s == "" {
+		return word, fmt.Errorf("signature is missing %s", name)
+	}
+	raw, err := HexDecode(s)
+	if err != nil {
+		return word, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	if len(raw) > 32 {
+		return word, fmt.Errorf("invalid %s: longer than 32 bytes", name)
+	}
+	copy(word[32-len(raw):], raw)
+	return word, nil
+}
+
+func decodeJSONNumber(raw json.RawMessage) (*big.Int, error) {
+	var str string
+	if err := json.Unmarshal(raw, &str); err != nil {
+		str = string(raw)
+	}
+	str = strings.TrimSpace(str)
+	if Has0xPrefix(str) {
+		raw, err := HexDecode(str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", str)
+		}
+		return new(big.Int).SetBytes(raw), nil
+	}
+	v, ok := new(big.Int).SetString(str, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid number %s", str)
+	}
+	return v, nil
+}
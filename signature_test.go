@@ -0,0 +1,155 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSignature(t *testing.T) {
+	const (
+		address = "0x2e988A386a799F506693793c6A5AF6B54dfAaBfB"
+		hexSig  = "0x9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76139c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f5507931c"
+		r       = "0x9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76"
+		s       = "0x139c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f550793"
+		vs      = "0x939c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f550793"
+	)
+	want, err := ParseHexSignature(hexSig)
+	assert.NoError(t, err)
+	assert.Equal(t, byte(28), want.V)
+	assert.Equal(t, byte(1), want.YParity())
+
+	tests := []struct {
+		name  string
+		parse func() (Signature, error)
+	}{
+		{
+			name: "bytes with recovery id",
+			parse: func() (Signature, error) {
+				sig := MustMustHexDecode(t, hexSig)
+				sig[64] = 1
+				return ParseSignature(sig)
+			},
+		},
+		{
+			name: "compact",
+			parse: func() (Signature, error) {
+				return ParseSignature(want.Compact())
+			},
+		},
+		{
+			name: "base64",
+			parse: func() (Signature, error) {
+				return ParseBase64Signature(want.Base64())
+			},
+		},
+		{
+			name: "json string",
+			parse: func() (Signature, error) {
+				return ParseJSONSignature([]byte(`"` + hexSig + `"`))
+			},
+		},
+		{
+			name: "ethers splitSignature",
+			parse: func() (Signature, error) {
+				return ParseJSONSignature([]byte(`{"r":"` + r + `","s":"` + s + `","_vs":"` + vs + `","recoveryParam":1,"v":28}`))
+			},
+		},
+		{
+			name: "ethers v6 yParity",
+			parse: func() (Signature, error) {
+				return ParseJSONSignature([]byte(`{"r":"` + r + `","s":"` + s + `","yParity":"0x1"}`))
+			},
+		},
+		{
+			name: "r and _vs only",
+			parse: func() (Signature, error) {
+				return ParseJSONSignature([]byte(`{"r":"` + r + `","yParityAndS":"` + vs + `"}`))
+			},
+		},
+		{
+			name: "marshaled",
+			parse: func() (Signature, error) {
+				raw, err := json.Marshal(want)
+				if err != nil {
+					return Signature{}, err
+				}
+				return ParseJSONSignature(raw)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parse()
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+			assert.Equal(t, hexSig, got.Hex())
+
+			valid, err := VerifyEllipticCurveParsedSignatureEx(common.HexToAddress(address), []byte("It's a small(er) world"), got)
+			assert.NoError(t, err)
+			assert.True(t, valid)
+		})
+	}
+
+	_, err = ParseJSONSignature([]byte(`{"r":"` + r + `","s":"` + s + `"}`))
+	assert.Error(t, err)
+	_, err = ParseJSONSignature([]byte(`{"r":"` + r + `","s":"` + s + `","v":29}`))
+	assert.Error(t, err)
+}
+
+func TestSignatureYParity(t *testing.T) {
+	tests := []struct {
+		v    byte
+		want byte
+	}{
+		{v: 27, want: 0},
+		{v: 28, want: 1},
+		{v: 0, want: 0},
+		{v: 1, want: 1},
+	}
+	for _, tt := range tests {
+		signature := Signature{V: tt.v}
+		assert.Equal(t, tt.want, signature.YParity(), "v %d", tt.v)
+		assert.Equal(t, 27+tt.want, signature.Bytes()[64], "v %d", tt.v)
+		_, err := signature.MarshalJSON()
+		assert.NoError(t, err, "v %d", tt.v)
+	}
+
+	// an invalid V is kept, so that it is rejected instead of being verified as another signature
+	want, err := ParseHexSignature("0x9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76139c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f5507931c")
+	assert.NoError(t, err)
+	for _, v := range []byte{2, 26, 29, 35, 99} {
+		signature := want
+		signature.V = v
+		assert.Equal(t, v, signature.YParity(), "v %d", v)
+		assert.Equal(t, v, signature.Bytes()[64], "v %d", v)
+		_, err := RecoveryAddressParsedEx(accounts.TextHash([]byte("hello")), signature)
+		assert.ErrorIs(t, err, ErrInvalidRecoveryID, "v %d", v)
+		_, err = signature.MarshalJSON()
+		assert.ErrorIs(t, err, ErrInvalidRecoveryID, "v %d", v)
+	}
+
+	var zero Signature
+	assert.Equal(t, byte(0), zero.YParity())
+	assert.Equal(t, byte(27), zero.Bytes()[64])
+	_, method, err := normalizeSignatureEx(zero.Bytes(), newRecoveryOptions(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, MethodECDSA, method)
+}
@@ -0,0 +1,604 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// SIWEField names a field of a Sign-In With Ethereum message, which is reported by SIWEError
+type SIWEField string
+
+const (
+	SIWEFieldMessage        SIWEField = "message"
+	SIWEFieldScheme         SIWEField = "scheme"
+	SIWEFieldDomain         SIWEField = "domain"
+	SIWEFieldAddress        SIWEField = "address"
+	SIWEFieldStatement      SIWEField = "statement"
+	SIWEFieldURI            SIWEField = "uri"
+	SIWEFieldVersion        SIWEField = "version"
+	SIWEFieldChainID        SIWEField = "chain-id"
+	SIWEFieldNonce          SIWEField = "nonce"
+	SIWEFieldIssuedAt       SIWEField = "issued-at"
+	SIWEFieldExpirationTime SIWEField = "expiration-time"
+	SIWEFieldNotBefore      SIWEField = "not-before"
+	SIWEFieldRequestID      SIWEField = "request-id"
+	SIWEFieldResources      SIWEField = "resources"
+	SIWEFieldSignature      SIWEField = "signature"
+)
+
+var (
+	// ErrSIWEMalformed is returned when a Sign-In With Ethereum message does not conform to the ABNF of EIP-4361
+	ErrSIWEMalformed = errors.New("malformed field")
+
+	// ErrSIWEMismatch is returned when a field of a Sign-In With Ethereum message is not the expected value
+	ErrSIWEMismatch = errors.New("does not match the expected value")
+
+	// ErrSIWEExpired is returned when the expiration time of a Sign-In With Ethereum message has passed
+	ErrSIWEExpired = errors.New("message has expired")
+
+	// ErrSIWENotYetValid is returned when the not before time of a Sign-In With Ethereum message has not come
+	ErrSIWENotYetValid = errors.New("message is not yet valid")
+
+	// ErrSIWEInvalidSignature is returned when the signature of a Sign-In With Ethereum message is not signed by its address
+	ErrSIWEInvalidSignature = errors.New("signature is not signed by the address")
+
+	// ErrSIWEMissingExpectation is returned when the verification of a Sign-In With Ethereum message is not given
+	// the expected value of a field that EIP-4361 requires the verifier to check, which are the domain and the nonce
+	ErrSIWEMissingExpectation = errors.New("expected value is not given")
+)
+
+// SIWEError reports the field of a Sign-In With Ethereum message that failed parsing or validation,
+// Err is one of the ErrSIWE errors, or the error of the signature verification.
+type SIWEError struct {
+	Field SIWEField
+	Err   error
+}
+
+// Error implements the error interface
+func (e *SIWEError) Error() string {
+	return fmt.Sprintf("siwe: %s: %v", e.Field, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *SIWEError) Unwrap() error {
+	return e.Err
+}
+
+func newSIWEError(field SIWEField, err error, format string, args ...interface{}) *SIWEError {
+	if format != "" {
+		err = fmt.Errorf("%w: %s", err, fmt.Sprintf(format, args...))
+	}
+	return &SIWEError{Field: field, Err: err}
+}
+
+const (
+	siweHeaderSuffix      = " wants you to sign in with your Ethereum account:"
+	siweTagURI            = "URI: "
+	siweTagVersion        = "Version: "
+	siweTagChainID        = "Chain ID: "
+	siweTagNonce          = "Nonce: "
+	siweTagIssuedAt       = "Issued At: "
+	siweTagExpirationTime = "Expiration Time: "
+	siweTagNotBefore      = "Not Before: "
+	siweTagRequestID      = "Request ID: "
+	siweTagResources      = "Resources:"
+	siweResourcePrefix    = "- "
+)
+
+// SIWEMessage is a Sign-In With Ethereum message defined by EIP-4361.
+// The times are kept as the RFC 3339 strings of the message, so that String returns exactly what was signed.
+type SIWEMessage struct {
+	// Scheme is the optional URI scheme of the origin of the request, such as "https"
+	Scheme string
+	// Domain is the RFC 3986 authority that is requesting the signing
+	Domain string
+	// Address is the account performing the signing, it is written in the EIP-55 checksum form
+	Address ethcommon.Address
+	// Statement is the optional human-readable assertion, which must not contain a new line
+	Statement string
+	// URI is the RFC 3986 URI referring to the resource that is the subject of the signing
+	URI string
+	// Version is the version of the message, which must be "1"
+	Version string
+	// ChainID is the EIP-155 chain id that the session is bound to
+	ChainID *big.Int
+	// Nonce is a random string of at least 8 alphanumeric characters to prevent replay attacks
+	Nonce string
+	// IssuedAt is the RFC 3339 time when the message was generated
+	IssuedAt string
+	// ExpirationTime is the optional RFC 3339 time after which the message is no longer valid
+	ExpirationTime string
+	// NotBefore is the optional RFC 3339 time before which the message is not yet valid
+	NotBefore string
+	// RequestID is an optional system-specific identifier
+	RequestID string
+	// Resources is the optional list of RFC 3986 URIs the user wishes to have resolved
+	Resources []string
+}
+
+// ParseSIWEMessage parses a Sign-In With Ethereum message strictly following the ABNF of EIP-4361,
+// a *SIWEError wrapping ErrSIWEMalformed reports the first malformed field.
+func ParseSIWEMessage(message string) (*SIWEMessage, error) {
+	lines := strings.Split(message, "\n")
+	p := &siweParser{lines: lines}
+	m := &SIWEMessage{}
+
+	header, ok := p.next()
+	if !ok || !strings.HasSuffix(header, siweHeaderSuffix) {
+		return nil, newSIWEError(SIWEFieldMessage, ErrSIWEMalformed, "missing %q", strings.TrimSpace(siweHeaderSuffix))
+	}
+	origin := strings.TrimSuffix(header, siweHeaderSuffix)
+	if i := strings.Index(origin, "://"); i >= 0 {
+		m.Scheme, origin = origin[:i], origin[i+3:]
+	}
+	m.Domain = origin
+
+	address, _ := p.next()
+	if !isChecksumAddress(address) {
+		return nil, newSIWEError(SIWEFieldAddress, ErrSIWEMalformed, "%q is not an EIP-55 checksum address", address)
+	}
+	m.Address = ethcommon.HexToAddress(address)
+	if line, ok := p.next(); !ok || line != "" {
+		return nil, newSIWEError(SIWEFieldStatement, ErrSIWEMalformed, "missing empty line after the address")
+	}
+	statement, _ := p.next()
+	if statement != "" {
+		m.Statement = statement
+		if line, ok := p.next(); !ok || line != "" {
+			return nil, newSIWEError(SIWEFieldStatement, ErrSIWEMalformed, "missing empty line after the statement")
+		}
+	}
+
+	var (
+		chainID string
+		err     error
+	)
+	for _, field := range []struct {
+		name     SIWEField
+		tag      string
+		value    *string
+		optional bool
+	}{
+		{SIWEFieldURI, siweTagURI, &m.URI, false},
+		{SIWEFieldVersion, siweTagVersion, &m.Version, false},
+		{SIWEFieldChainID, siweTagChainID, &chainID, false},
+		{SIWEFieldNonce, siweTagNonce, &m.Nonce, false},
+		{SIWEFieldIssuedAt, siweTagIssuedAt, &m.IssuedAt, false},
+		{SIWEFieldExpirationTime, siweTagExpirationTime, &m.ExpirationTime, true},
+		{SIWEFieldNotBefore, siweTagNotBefore, &m.NotBefore, true},
+		{SIWEFieldRequestID, siweTagRequestID, &m.RequestID, true},
+	} {
+		value, ok := p.tagged(field.tag)
+		if !ok {
+			if field.optional {
+				continue
+			}
+			return nil, newSIWEError(field.name, ErrSIWEMalformed, "missing %q", strings.TrimSpace(field.tag))
+		}
+		*field.value = value
+	}
+	if rest, ok := p.tagged(siweTagResources); ok {
+		if rest != "" {
+			return nil, newSIWEError(SIWEFieldResources, ErrSIWEMalformed, "unexpected %q after %q", rest, siweTagResources)
+		}
+		for {
+			resource, ok := p.tagged(siweResourcePrefix)
+			if !ok {
+				break
+			}
+			m.Resources = append(m.Resources, resource)
+		}
+		if len(m.Resources) == 0 {
+			// String omits the line without resources, so it would not reproduce the signed message
+			return nil, newSIWEError(SIWEFieldResources, ErrSIWEMalformed, "%q is not followed by any resource", siweTagResources)
+		}
+	}
+	if line, ok := p.next(); ok {
+		return nil, newSIWEError(SIWEFieldMessage, ErrSIWEMalformed, "unexpected line %q", line)
+	}
+	if m.ChainID, err = parseSIWEChainID(chainID); err != nil {
+		return nil, err
+	}
+	if err := m.validateSyntax(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type siweParser struct {
+	lines []string
+	pos   int
+}
+
+func (p *siweParser) next() (string, bool) {
+	if p.pos >= len(p.lines) {
+		return "", false
+	}
+	p.pos++
+	return p.lines[p.pos-1], true
+}
+
+// tagged consumes the next line if it starts with tag, and returns the rest of the line
+func (p *siweParser) tagged(tag string) (string, bool) {
+	if p.pos >= len(p.lines) || !strings.HasPrefix(p.lines[p.pos], tag) {
+		return "", false
+	}
+	p.pos++
+	return strings.TrimPrefix(p.lines[p.pos-1], tag), true
+}
+
+func parseSIWEChainID(s string) (*big.Int, error) {
+	if s == "" || strings.Trim(s, "0123456789") != "" {
+		return nil, newSIWEError(SIWEFieldChainID, ErrSIWEMalformed, "%q is not a decimal number", s)
+	}
+	chainID, _ := new(big.Int).SetString(s, 10)
+	return chainID, nil
+}
+
+// String serializes the message as defined by EIP-4361, which is the text to be signed
+func (m *SIWEMessage) String() string {
+	var b strings.Builder
+	if m.Scheme != "" {
+		b.WriteString(m.Scheme + "://")
+	}
+	b.WriteString(m.Domain + siweHeaderSuffix + "\n")
+	b.WriteString(m.Address.Hex() + "\n\n")
+	if m.Statement != "" {
+		b.WriteString(m.Statement + "\n")
+	}
+	b.WriteString("\n")
+	chainID := m.ChainID
+	if chainID == nil {
+		chainID = new(big.Int)
+	}
+	b.WriteString(siweTagURI + m.URI + "\n")
+	b.WriteString(siweTagVersion + m.Version + "\n")
+	b.WriteString(siweTagChainID + chainID.String() + "\n")
+	b.WriteString(siweTagNonce + m.Nonce + "\n")
+	b.WriteString(siweTagIssuedAt + m.IssuedAt)
+	if m.ExpirationTime != "" {
+		b.WriteString("\n" + siweTagExpirationTime + m.ExpirationTime)
+	}
+	if m.NotBefore != "" {
+		b.WriteString("\n" + siweTagNotBefore + m.NotBefore)
+	}
+	if m.RequestID != "" {
+		b.WriteString("\n" + siweTagRequestID + m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		b.WriteString("\n" + siweTagResources)
+		for _, resource := range m.Resources {
+			b.WriteString("\n" + siweResourcePrefix + resource)
+		}
+	}
+	return b.String()
+}
+
+// validateSyntax checks every field against the ABNF of EIP-4361
+func (m *SIWEMessage) validateSyntax() error {
+	if m.Scheme != "" && !isSIWEScheme(m.Scheme) {
+		return newSIWEError(SIWEFieldScheme, ErrSIWEMalformed, "%q is not a URI scheme", m.Scheme)
+	}
+	if !isSIWEAuthority(m.Domain) {
+		return newSIWEError(SIWEFieldDomain, ErrSIWEMalformed, "%q is not an RFC 3986 authority", m.Domain)
+	}
+	if !isSIWEChars(m.Statement, siweUnreserved+siweReserved+" ") {
+		return newSIWEError(SIWEFieldStatement, ErrSIWEMalformed, "statement may only contain reserved, unreserved and space characters")
+	}
+	if !isSIWEURI(m.URI) {
+		return newSIWEError(SIWEFieldURI, ErrSIWEMalformed, "%q is not an RFC 3986 URI", m.URI)
+	}
+	if m.Version != "1" {
+		return newSIWEError(SIWEFieldVersion, ErrSIWEMalformed, "version must be 1")
+	}
+	if m.ChainID == nil || m.ChainID.Sign() < 0 {
+		return newSIWEError(SIWEFieldChainID, ErrSIWEMalformed, "chain id must be a decimal number")
+	}
+	if len(m.Nonce) < 8 || !isSIWEChars(m.Nonce, siweAlphaDigit) {
+		return newSIWEError(SIWEFieldNonce, ErrSIWEMalformed, "nonce must be at least 8 alphanumeric characters")
+	}
+	for _, t := range []struct {
+		name     SIWEField
+		value    string
+		optional bool
+	}{
+		{SIWEFieldIssuedAt, m.IssuedAt, false},
+		{SIWEFieldExpirationTime, m.ExpirationTime, true},
+		{SIWEFieldNotBefore, m.NotBefore, true},
+	} {
+		if t.value == "" && t.optional {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339Nano, t.value); err != nil {
+			return newSIWEError(t.name, ErrSIWEMalformed, "%q is not an RFC 3339 date-time", t.value)
+		}
+	}
+	if !isSIWEPChars(m.RequestID) {
+		return newSIWEError(SIWEFieldRequestID, ErrSIWEMalformed, "request id may only contain pchar characters")
+	}
+	for _, resource := range m.Resources {
+		if !isSIWEURI(resource) {
+			return newSIWEError(SIWEFieldResources, ErrSIWEMalformed, "%q is not an RFC 3986 URI", resource)
+		}
+	}
+	return nil
+}
+
+// SIWEOption sets what a Sign-In With Ethereum message is expected to be
+type SIWEOption func(*siweOptions)
+
+type siweOptions struct {
+	scheme    *string
+	domain    *string
+	uri       *string
+	chainID   *big.Int
+	nonce     *string
+	resources []string
+	now       func() time.Time
+	verify    []VerifyOption
+}
+
+func newSIWEOptions(opts []SIWEOption) *siweOptions {
+	options := &siweOptions{
+		now: time.Now,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WithSIWEScheme requires the scheme of the message to be scheme, "" requires the message to have no scheme
+func WithSIWEScheme(scheme string) SIWEOption {
+	return func(o *siweOptions) {
+		o.scheme = &scheme
+	}
+}
+
+// WithSIWEDomain requires the domain of the message to be domain, which should be the domain the user is signing in to
+func WithSIWEDomain(domain string) SIWEOption {
+	return func(o *siweOptions) {
+		o.domain = &domain
+	}
+}
+
+// WithSIWEURI requires the URI of the message to be uri
+func WithSIWEURI(uri string) SIWEOption {
+	return func(o *siweOptions) {
+		o.uri = &uri
+	}
+}
+
+// WithSIWEChainID requires the chain id of the message to be chainID
+func WithSIWEChainID(chainID *big.Int) SIWEOption {
+	return func(o *siweOptions) {
+		o.chainID = chainID
+	}
+}
+
+// WithSIWENonce requires the nonce of the message to be nonce, which should be the one issued to the session
+func WithSIWENonce(nonce string) SIWEOption {
+	return func(o *siweOptions) {
+		o.nonce = &nonce
+	}
+}
+
+// WithSIWEResources requires the message to list exactly the given resources, in any order
+func WithSIWEResources(resources ...string) SIWEOption {
+	return func(o *siweOptions) {
+		o.resources = append([]string{}, resources...)
+	}
+}
+
+// WithSIWEClock sets the clock used to check the expiration time and the not before time, the default is time.Now
+func WithSIWEClock(now func() time.Time) SIWEOption {
+	return func(o *siweOptions) {
+		o.now = now
+	}
+}
+
+// WithSIWEVerifyOptions applies the given VerifyOption to the signature verification, such as WithBlockNumber
+func WithSIWEVerifyOptions(opts ...VerifyOption) SIWEOption {
+	return func(o *siweOptions) {
+		o.verify = append(o.verify, opts...)
+	}
+}
+
+// Validate checks the message against the ABNF of EIP-4361, the expectations given by opts
+// and the clock, the signature is not verified. A *SIWEError reports the first failed field.
+func (m *SIWEMessage) Validate(opts ...SIWEOption) error {
+	return m.validate(newSIWEOptions(opts))
+}
+
+func (m *SIWEMessage) validate(options *siweOptions) error {
+	if err := m.validateSyntax(); err != nil {
+		return err
+	}
+	if options.scheme != nil && m.Scheme != *options.scheme {
+		return newSIWEError(SIWEFieldScheme, ErrSIWEMismatch, "got %q, expected %q", m.Scheme, *options.scheme)
+	}
+	if options.domain != nil && m.Domain != *options.domain {
+		return newSIWEError(SIWEFieldDomain, ErrSIWEMismatch, "got %q, expected %q", m.Domain, *options.domain)
+	}
+	if options.uri != nil && m.URI != *options.uri {
+		return newSIWEError(SIWEFieldURI, ErrSIWEMismatch, "got %q, expected %q", m.URI, *options.uri)
+	}
+	if options.chainID != nil && m.ChainID.Cmp(options.chainID) != 0 {
+		return newSIWEError(SIWEFieldChainID, ErrSIWEMismatch, "got %s, expected %s", m.ChainID, options.chainID)
+	}
+	if options.nonce != nil && m.Nonce != *options.nonce {
+		return newSIWEError(SIWEFieldNonce, ErrSIWEMismatch, "got %q, expected %q", m.Nonce, *options.nonce)
+	}
+	if options.resources != nil && !sameStringSet(m.Resources, options.resources) {
+		return newSIWEError(SIWEFieldResources, ErrSIWEMismatch, "got %q, expected %q", m.Resources, options.resources)
+	}
+	now := options.now()
+	if m.ExpirationTime != "" {
+		expirationTime, _ := time.Parse(time.RFC3339Nano, m.ExpirationTime)
+		if !now.Before(expirationTime) {
+			return newSIWEError(SIWEFieldExpirationTime, ErrSIWEExpired, "expired at %s", m.ExpirationTime)
+		}
+	}
+	if m.NotBefore != "" {
+		notBefore, _ := time.Parse(time.RFC3339Nano, m.NotBefore)
+		if now.Before(notBefore) {
+			return newSIWEError(SIWEFieldNotBefore, ErrSIWENotYetValid, "valid from %s", m.NotBefore)
+		}
+	}
+	return nil
+}
+
+// VerifySIWEMessage parses and validates a Sign-In With Ethereum message like ParseSIWEMessage and Validate,
+// and verifies that signature is signed by its address through VerifySignatureEx, so that both EOAs and
+// ERC1271 smart contract wallets can sign in. The signature is verified over the message as it is given.
+// EIP-4361 requires the verifier to check the domain and the nonce, so WithSIWEDomain and WithSIWENonce must be
+// given, otherwise ErrSIWEMissingExpectation is returned before the message is parsed.
+// A *SIWEError reports the first failed field, a signature that is not signed by the address is reported as
+// ErrSIWEInvalidSignature, including an EOA whose ERC1271 fallback finds no code, and the other errors
+// of the contract calls are wrapped with SIWEFieldSignature.
+func VerifySIWEMessage(ctx context.Context, caller bind.ContractCaller, message string, signature []byte, opts ...SIWEOption) (*SIWEMessage, error) {
+	options := newSIWEOptions(opts)
+	if options.nonce == nil {
+		return nil, newSIWEError(SIWEFieldNonce, ErrSIWEMissingExpectation, "use WithSIWENonce")
+	}
+	return verifySIWEMessage(ctx, caller, message, signature, options)
+}
+
+// verifySIWEMessage is VerifySIWEMessage without the check of the nonce expectation
+func verifySIWEMessage(ctx context.Context, caller bind.ContractCaller, message string, signature []byte, options *siweOptions) (*SIWEMessage, error) {
+	if options.domain == nil {
+		return nil, newSIWEError(SIWEFieldDomain, ErrSIWEMissingExpectation, "use WithSIWEDomain")
+	}
+	m, err := ParseSIWEMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.validate(options); err != nil {
+		return m, err
+	}
+	valid, err := VerifySignatureEx(ctx, caller, m.Address, []byte(message), signature, options.verify...)
+	if errors.Is(err, ErrNoContractCode) {
+		// the address is an EOA and the signature is not signed by it
+		return m, newSIWEError(SIWEFieldSignature, ErrSIWEInvalidSignature, "%v", err)
+	}
+	if err != nil {
+		return m, &SIWEError{Field: SIWEFieldSignature, Err: err}
+	}
+	if !valid {
+		return m, &SIWEError{Field: SIWEFieldSignature, Err: ErrSIWEInvalidSignature}
+	}
+	return m, nil
+}
+
+// VerifySIWEHexSignature is like VerifySIWEMessage but accepts a hex encoded signature
+func VerifySIWEHexSignature(ctx context.Context, caller bind.ContractCaller, message string, signature string, opts ...SIWEOption) (*SIWEMessage, error) {
+	sig, err := HexDecode(signature)
+	if err != nil {
+		return nil, &SIWEError{Field: SIWEFieldSignature, Err: err}
+	}
+	return VerifySIWEMessage(ctx, caller, message, sig, opts...)
+}
+
+const (
+	siweAlphaDigit  = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	siweUnreserved  = siweAlphaDigit + "-._~"
+	siweSubDelims   = "!$&'()*+,;="
+	siweReserved    = ":/?#[]@" + siweSubDelims
+	siwePCharsNoPct = siweUnreserved + siweSubDelims + ":@"
+)
+
+func isSIWEChars(s string, allowed string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune(allowed, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSIWEPChars reports whether s is *pchar, pct-encoded characters included
+func isSIWEPChars(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' {
+			if i+2 >= len(s) || !isHexChar(s[i+1]) || !isHexChar(s[i+2]) {
+				return false
+			}
+			i += 2
+			continue
+		}
+		if !strings.ContainsRune(siwePCharsNoPct, rune(s[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func isHexChar(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
+func isSIWEScheme(s string) bool {
+	if s == "" || !strings.ContainsRune(siweAlphaDigit[:52], rune(s[0])) {
+		return false
+	}
+	return isSIWEChars(s, siweAlphaDigit+"+-.")
+}
+
+func isSIWEAuthority(s string) bool {
+	if s == "" || !isSIWEChars(s, siweUnreserved+siweSubDelims+":@[]%") {
+		return false
+	}
+	u, err := url.Parse("siwe://" + s)
+	return err == nil && u.Host != "" && u.Path == "" && u.RawQuery == "" && u.Fragment == ""
+}
+
+func isSIWEURI(s string) bool {
+	if !isSIWEChars(s, siweUnreserved+siweReserved+"%") {
+		return false
+	}
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && isSIWEScheme(u.Scheme)
+}
+
+func isChecksumAddress(s string) bool {
+	return ethcommon.IsHexAddress(s) && Has0xPrefix(s) && ethcommon.HexToAddress(s).Hex() == s
+}
+
+func sameStringSet(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, s := range a {
+		counts[s]++
+	}
+	for _, s := range b {
+		if counts[s] == 0 {
+			return false
+		}
+		counts[s]--
+	}
+	return true
+}
@@ -0,0 +1,222 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const siweExample = `https://service.org wants you to sign in with your Ethereum account:
+0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2
+
+I accept the ServiceOrg Terms of Service: https://service.org/tos
+
+URI: https://service.org/login
+Version: 1
+Chain ID: 1
+Nonce: 32891756
+Issued At: 2021-09-30T16:25:24Z
+Expiration Time: 2021-10-30T16:25:24.000Z
+Not Before: 2021-09-30T16:25:24Z
+Request ID: some-request%20id
+Resources:
+- ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq/
+- https://example.com/my-web2-claim.json`
+
+func siweMessageFor(address common.Address) *SIWEMessage {
+	return &SIWEMessage{
+		Domain:    "example.com",
+		Address:   address,
+		Statement: "Sign in to example.com",
+		URI:       "https://example.com/login",
+		Version:   "1",
+		ChainID:   big.NewInt(1),
+		Nonce:     "abcdef1234",
+		IssuedAt:  "2022-01-01T00:00:00Z",
+	}
+}
+
+func assertSIWEError(t *testing.T, err error, field SIWEField, target error) {
+	var siweErr *SIWEError
+	if assert.True(t, errors.As(err, &siweErr), "not a SIWEError: %v", err) {
+		assert.Equal(t, field, siweErr.Field)
+	}
+	assert.ErrorIs(t, err, target)
+}
+
+func TestParseSIWEMessage(t *testing.T) {
+	m, err := ParseSIWEMessage(siweExample)
+	assert.NoError(t, err)
+	assert.Equal(t, &SIWEMessage{
+		Scheme:         "https",
+		Domain:         "service.org",
+		Address:        common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"),
+		Statement:      "I accept the ServiceOrg Terms of Service: https://service.org/tos",
+		URI:            "https://service.org/login",
+		Version:        "1",
+		ChainID:        big.NewInt(1),
+		Nonce:          "32891756",
+		IssuedAt:       "2021-09-30T16:25:24Z",
+		ExpirationTime: "2021-10-30T16:25:24.000Z",
+		NotBefore:      "2021-09-30T16:25:24Z",
+		RequestID:      "some-request%20id",
+		Resources: []string{
+			"ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq/",
+			"https://example.com/my-web2-claim.json",
+		},
+	}, m)
+	assert.Equal(t, siweExample, m.String())
+
+	minimal := siweMessageFor(common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"))
+	minimal.Statement = ""
+	parsed, err := ParseSIWEMessage(minimal.String())
+	assert.NoError(t, err)
+	assert.Equal(t, minimal, parsed)
+	assert.Contains(t, minimal.String(), "Cc2\n\n\nURI: ")
+
+	tests := []struct {
+		name    string
+		message string
+		field   SIWEField
+	}{
+		{"empty", "", SIWEFieldMessage},
+		{"header", strings.Replace(siweExample, "wants you to sign in", "wants you to log in", 1), SIWEFieldMessage},
+		{"scheme", strings.Replace(siweExample, "https://service.org", "1https://service.org", 1), SIWEFieldScheme},
+		{"domain", strings.Replace(siweExample, "https://service.org wants", "https://service.org/path wants", 1), SIWEFieldDomain},
+		{"checksum", strings.Replace(siweExample, "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2", "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", 1), SIWEFieldAddress},
+		{"statement line", strings.Replace(siweExample, "tos\n\nURI", "tos\nURI", 1), SIWEFieldStatement},
+		{"statement chars", strings.Replace(siweExample, "I accept", "I \"accept\"", 1), SIWEFieldStatement},
+		{"uri", strings.Replace(siweExample, "URI: https://service.org/login", "URI: service.org/login", 1), SIWEFieldURI},
+		{"version", strings.Replace(siweExample, "Version: 1", "Version: 2", 1), SIWEFieldVersion},
+		{"missing version", strings.Replace(siweExample, "Version: 1\n", "", 1), SIWEFieldVersion},
+		{"chain id", strings.Replace(siweExample, "Chain ID: 1", "Chain ID: 0x1", 1), SIWEFieldChainID},
+		{"short nonce", strings.Replace(siweExample, "Nonce: 32891756", "Nonce: 1234567", 1), SIWEFieldNonce},
+		{"nonce chars", strings.Replace(siweExample, "Nonce: 32891756", "Nonce: 3289-1756", 1), SIWEFieldNonce},
+		{"issued at", strings.Replace(siweExample, "Issued At: 2021-09-30T16:25:24Z", "Issued At: 2021-09-30", 1), SIWEFieldIssuedAt},
+		{"expiration time", strings.Replace(siweExample, "Expiration Time: 2021-10-30T16:25:24.000Z", "Expiration Time: tomorrow", 1), SIWEFieldExpirationTime},
+		{"not before", strings.Replace(siweExample, "Not Before: 2021-09-30T16:25:24Z", "Not Before: 2021-09-30 16:25:24", 1), SIWEFieldNotBefore},
+		{"request id", strings.Replace(siweExample, "some-request%20id", "some request", 1), SIWEFieldRequestID},
+		{"resource", strings.Replace(siweExample, "- https://example.com/my-web2-claim.json", "- my-web2-claim.json", 1), SIWEFieldResources},
+		{"resources tag", strings.Replace(siweExample, "Resources:", "Resources:junk", 1), SIWEFieldResources},
+		{"resources without items", siweExample[:strings.Index(siweExample, "\n- ")], SIWEFieldResources},
+		{"field order", strings.Replace(siweExample, "Version: 1\nChain ID: 1", "Chain ID: 1\nVersion: 1", 1), SIWEFieldVersion},
+		{"trailing new line", siweExample + "\n", SIWEFieldMessage},
+		{"carriage return", strings.ReplaceAll(siweExample, "\n", "\r\n"), SIWEFieldMessage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSIWEMessage(tt.message)
+			assertSIWEError(t, err, tt.field, ErrSIWEMalformed)
+		})
+	}
+}
+
+func TestSIWEMessageValidate(t *testing.T) {
+	m, err := ParseSIWEMessage(siweExample)
+	assert.NoError(t, err)
+	clock := func(s string) SIWEOption {
+		now, err := time.Parse(time.RFC3339, s)
+		assert.NoError(t, err)
+		return WithSIWEClock(func() time.Time { return now })
+	}
+	valid := clock("2021-10-01T00:00:00Z")
+	tests := []struct {
+		name   string
+		opts   []SIWEOption
+		field  SIWEField
+		target error
+	}{
+		{
+			name: "all expected",
+			opts: []SIWEOption{
+				valid,
+				WithSIWEScheme("https"),
+				WithSIWEDomain("service.org"),
+				WithSIWEURI("https://service.org/login"),
+				WithSIWEChainID(big.NewInt(1)),
+				WithSIWENonce("32891756"),
+				WithSIWEResources("https://example.com/my-web2-claim.json", "ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq/"),
+			},
+		},
+		{"scheme", []SIWEOption{valid, WithSIWEScheme("")}, SIWEFieldScheme, ErrSIWEMismatch},
+		{"domain", []SIWEOption{valid, WithSIWEDomain("evil.org")}, SIWEFieldDomain, ErrSIWEMismatch},
+		{"uri", []SIWEOption{valid, WithSIWEURI("https://service.org/")}, SIWEFieldURI, ErrSIWEMismatch},
+		{"chain id", []SIWEOption{valid, WithSIWEChainID(big.NewInt(5))}, SIWEFieldChainID, ErrSIWEMismatch},
+		{"nonce", []SIWEOption{valid, WithSIWENonce("12345678")}, SIWEFieldNonce, ErrSIWEMismatch},
+		{"resources", []SIWEOption{valid, WithSIWEResources("https://example.com/my-web2-claim.json")}, SIWEFieldResources, ErrSIWEMismatch},
+		{"expired", []SIWEOption{clock("2021-10-30T16:25:24Z")}, SIWEFieldExpirationTime, ErrSIWEExpired},
+		{"not yet valid", []SIWEOption{clock("2021-09-30T16:25:23Z")}, SIWEFieldNotBefore, ErrSIWENotYetValid},
+		{"default clock", nil, SIWEFieldExpirationTime, ErrSIWEExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.Validate(tt.opts...)
+			if tt.target == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assertSIWEError(t, err, tt.field, tt.target)
+		})
+	}
+
+	m.Nonce = "short"
+	assertSIWEError(t, m.Validate(valid), SIWEFieldNonce, ErrSIWEMalformed)
+}
+
+func TestVerifySIWEMessage(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	ctx := context.Background()
+	eoaMessage := siweMessageFor(wallets.eoa).String()
+	walletMessage := siweMessageFor(wallets.owner).String()
+	opts := []SIWEOption{WithSIWEDomain("example.com"), WithSIWENonce("abcdef1234")}
+
+	m, err := VerifySIWEMessage(ctx, wallets.backend, eoaMessage, wallets.sign(t, []byte(eoaMessage)), opts...)
+	assert.NoError(t, err)
+	assert.Equal(t, wallets.eoa, m.Address)
+
+	m, err = VerifySIWEHexSignature(ctx, wallets.backend, walletMessage, hexutil.Encode(wallets.sign(t, []byte(walletMessage))), opts...)
+	assert.NoError(t, err)
+	assert.Equal(t, wallets.owner, m.Address)
+
+	_, err = VerifySIWEMessage(ctx, wallets.backend, eoaMessage, wallets.sign(t, []byte(walletMessage)), opts...)
+	assertSIWEError(t, err, SIWEFieldSignature, ErrSIWEInvalidSignature)
+
+	_, err = VerifySIWEMessage(ctx, wallets.backend, walletMessage, wallets.sign(t, []byte(walletMessage)), WithSIWEDomain("example.com"), WithSIWENonce("otherNonce"))
+	assertSIWEError(t, err, SIWEFieldNonce, ErrSIWEMismatch)
+
+	_, err = VerifySIWEMessage(ctx, wallets.backend, walletMessage, wallets.sign(t, []byte(walletMessage)), WithSIWEDomain("example.com"))
+	assertSIWEError(t, err, SIWEFieldNonce, ErrSIWEMissingExpectation)
+
+	_, err = VerifySIWEMessage(ctx, wallets.backend, walletMessage, wallets.sign(t, []byte(walletMessage)), WithSIWENonce("abcdef1234"))
+	assertSIWEError(t, err, SIWEFieldDomain, ErrSIWEMissingExpectation)
+
+	_, err = VerifySIWEHexSignature(ctx, wallets.backend, walletMessage, "0xzz", opts...)
+	var siweErr *SIWEError
+	assert.True(t, errors.As(err, &siweErr))
+	assert.Equal(t, SIWEFieldSignature, siweErr.Field)
+
+	reverting := siweMessageFor(wallets.reverting).String()
+	_, err = VerifySIWEMessage(ctx, wallets.backend, reverting, wallets.sign(t, []byte(reverting)), opts...)
+	assertSIWEError(t, err, SIWEFieldSignature, ErrExecutionReverted)
+}
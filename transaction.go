@@ -0,0 +1,192 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// BlobTxType is the type of EIP-4844 blob transactions
+	BlobTxType = 0x03
+	// SetCodeTxType is the type of EIP-7702 set-code transactions
+	SetCodeTxType = 0x04
+)
+
+// ErrUnsupportedTransactionType is returned when the type of a raw transaction is not supported
+var ErrUnsupportedTransactionType = errors.New("unsupported transaction type")
+
+// manualTxFields is the number of RLP fields of the transaction types that are decoded without go-ethereum,
+// the last three fields are always y_parity, r and s
+var manualTxFields = map[byte]int{
+	BlobTxType:    14,
+	SetCodeTxType: 13,
+}
+
+// TransactionSender is the sender of a signed transaction, recovered by RecoverTransactionSender
+type TransactionSender struct {
+	// From is the address that signed the transaction
+	From ethcommon.Address
+	// Type is the EIP-2718 type of the transaction, which is 0 for legacy transactions
+	Type uint8
+	// ChainID is the chain the transaction is bound to, it is nil for legacy transactions signed before EIP-155
+	ChainID *big.Int
+	// Hash is the transaction hash
+	Hash ethcommon.Hash
+	// SigningHash is the hash signed by the sender
+	SigningHash ethcommon.Hash
+}
+
+// RecoverTransactionSenderHex is like RecoverTransactionSender but accepts a hex encoded transaction
+func RecoverTransactionSenderHex(raw string) (*TransactionSender, error) {
+	data, err := HexDecode(raw)
+	if err != nil {
+		return nil, err
+	}
+	return RecoverTransactionSender(data)
+}
+
+// RecoverTransactionSender recovers the sender of a raw signed transaction, as returned by eth_signTransaction
+// or sent by eth_sendRawTransaction. Legacy transactions (with or without EIP-155 replay protection) are RLP lists,
+// the other types are EIP-2718 envelopes: EIP-2930 (0x01), EIP-1559 (0x02), EIP-4844 (0x03, with or without
+// the blobs of the network form) and EIP-7702 (0x04). Other types are rejected with ErrUnsupportedTransactionType.
+// As required for transactions, signatures whose s value is greater than secp256k1n/2 are rejected with ErrHighS.
+func RecoverTransactionSender(raw []byte) (*TransactionSender, error) {
+	if len(raw) == 0 {
+		return nil, errors.New("empty transaction")
+	}
+	if _, ok := manualTxFields[raw[0]]; ok {
+		return recoverManualTransactionSender(raw)
+	}
+	if raw[0] < 0xc0 && raw[0] > types.DynamicFeeTxType {
+		return nil, fmt.Errorf("%w 0x%02x", ErrUnsupportedTransactionType, raw[0])
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, err
+	}
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.LatestSignerForChainID(tx.ChainId())
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, err
+	}
+	sender := &TransactionSender{
+		From:        from,
+		Type:        tx.Type(),
+		Hash:        tx.Hash(),
+		SigningHash: signer.Hash(tx),
+	}
+	if tx.Protected() {
+		sender.ChainID = tx.ChainId()
+	}
+	return sender, nil
+}
+
+// recoverManualTransactionSender recovers the sender of the transaction types that go-ethereum does not decode,
+// whose payload is rlp([chain_id, ..., y_parity, r, s]) and whose signing hash is keccak256(type || rlp([chain_id, ...]))
+func recoverManualTransactionSender(raw []byte) (*TransactionSender, error) {
+	txType := raw[0]
+	payload := raw[1:]
+	fields, err := splitRLPList(payload)
+	if err != nil {
+		return nil, err
+	}
+	if txType == BlobTxType && len(fields) == 4 {
+		// network form: rlp([tx_payload_body, blobs, commitments, proofs])
+		payload = fields[0]
+		if fields, err = splitRLPList(payload); err != nil {
+			return nil, err
+		}
+	}
+	if len(fields) != manualTxFields[txType] {
+		return nil, fmt.Errorf("invalid transaction of type 0x%02x: %d fields, expected %d", txType, len(fields), manualTxFields[txType])
+	}
+	unsigned, err := rlp.EncodeToBytes(fields[:len(fields)-3])
+	if err != nil {
+		return nil, err
+	}
+	var (
+		chainID = new(big.Int)
+		yParity uint64
+		r       = new(big.Int)
+		s       = new(big.Int)
+		n       = len(fields)
+	)
+	if err := rlp.DecodeBytes(fields[0], chainID); err != nil {
+		return nil, fmt.Errorf("invalid chain id: %w", err)
+	}
+	if err := rlp.DecodeBytes(fields[n-3], &yParity); err != nil {
+		return nil, fmt.Errorf("invalid y_parity: %w", err)
+	}
+	if err := rlp.DecodeBytes(fields[n-2], r); err != nil {
+		return nil, fmt.Errorf("invalid r: %w", err)
+	}
+	if err := rlp.DecodeBytes(fields[n-1], s); err != nil {
+		return nil, fmt.Errorf("invalid s: %w", err)
+	}
+	if yParity > 1 {
+		return nil, fmt.Errorf("%w (y_parity is not 0 or 1)", ErrInvalidRecoveryID)
+	}
+	if r.BitLen() > 256 || s.BitLen() > 256 {
+		return nil, errors.New("invalid transaction: r and s must be 256-bit unsigned integers")
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	sig[crypto.RecoveryIDOffset] = byte(yParity) + 27
+	signingHash := crypto.Keccak256Hash([]byte{txType}, unsigned)
+	from, err := RecoveryAddress(signingHash[:], sig, WithStrictLowS())
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionSender{
+		From:        from,
+		Type:        txType,
+		ChainID:     chainID,
+		Hash:        crypto.Keccak256Hash([]byte{txType}, payload),
+		SigningHash: signingHash,
+	}, nil
+}
+
+// splitRLPList splits the RLP list data into the encodings of its elements
+func splitRLPList(data []byte) ([]rlp.RawValue, error) {
+	content, rest, err := rlp.SplitList(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("invalid transaction: trailing bytes after the RLP list")
+	}
+	var fields []rlp.RawValue
+	for len(content) > 0 {
+		_, _, tail, err := rlp.Split(content)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, content[:len(content)-len(tail)])
+		content = tail
+	}
+	return fields, nil
+}
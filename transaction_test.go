@@ -0,0 +1,115 @@
+// Copyright 2022 storyicon@foxmail.com
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigverify
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+)
+
+// signManualTransaction signs rlp(fields) as a transaction of txType, and returns the raw transaction
+// together with its signing hash
+func signManualTransaction(t *testing.T, key *ecdsa.PrivateKey, txType byte, fields []interface{}) ([]byte, []byte, common.Hash) {
+	unsigned, err := rlp.EncodeToBytes(fields)
+	assert.NoError(t, err)
+	signingHash := crypto.Keccak256Hash([]byte{txType}, unsigned)
+	sig, err := crypto.Sign(signingHash[:], key)
+	assert.NoError(t, err)
+	signed := append(fields, sig[crypto.RecoveryIDOffset], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]))
+	body, err := rlp.EncodeToBytes(signed)
+	assert.NoError(t, err)
+	return append([]byte{txType}, body...), body, signingHash
+}
+
+func TestRecoverTransactionSender(t *testing.T) {
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("sender")))
+	assert.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0x63c0c19a282a1B52b07dD5a65b58948A07DAE32B")
+	chainID := big.NewInt(1337)
+
+	type testCase struct {
+		name            string
+		raw             []byte
+		wantType        uint8
+		wantChainID     *big.Int
+		wantHash        common.Hash
+		wantSigningHash common.Hash
+	}
+	var tests []testCase
+	gethTx := func(name string, signer types.Signer, data types.TxData, chainID *big.Int) {
+		tx, err := types.SignNewTx(key, signer, data)
+		assert.NoError(t, err)
+		raw, err := tx.MarshalBinary()
+		assert.NoError(t, err)
+		tests = append(tests, testCase{name: name, raw: raw, wantType: tx.Type(), wantChainID: chainID, wantHash: tx.Hash(), wantSigningHash: signer.Hash(tx)})
+	}
+	gethTx("legacy", types.HomesteadSigner{}, &types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(1)}, nil)
+	gethTx("legacy eip155", types.NewEIP155Signer(chainID), &types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(1)}, chainID)
+	gethTx("eip2930", types.NewEIP2930Signer(chainID), &types.AccessListTx{ChainID: chainID, Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000, To: &to}, chainID)
+	gethTx("eip1559", types.NewLondonSigner(chainID), &types.DynamicFeeTx{ChainID: chainID, Nonce: 1, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), Gas: 21000, To: &to}, chainID)
+
+	// chain_id, nonce, max_priority_fee_per_gas, max_fee_per_gas, gas_limit, to, value, data, access_list,
+	// max_fee_per_blob_gas, blob_versioned_hashes
+	blobFields := []interface{}{chainID, uint64(1), big.NewInt(1), big.NewInt(2), uint64(21000), to, big.NewInt(0), []byte{}, []interface{}{},
+		big.NewInt(3), []common.Hash{common.HexToHash("0x01")}}
+	raw, body, signingHash := signManualTransaction(t, key, BlobTxType, blobFields)
+	tests = append(tests, testCase{name: "eip4844", raw: raw, wantType: BlobTxType, wantChainID: chainID, wantHash: crypto.Keccak256Hash(raw), wantSigningHash: signingHash})
+	wrapper, err := rlp.EncodeToBytes([]interface{}{rlp.RawValue(body), [][]byte{{0x01}}, [][]byte{{0x02}}, [][]byte{{0x03}}})
+	assert.NoError(t, err)
+	tests = append(tests, testCase{name: "eip4844 network form", raw: append([]byte{BlobTxType}, wrapper...), wantType: BlobTxType, wantChainID: chainID, wantHash: crypto.Keccak256Hash(raw), wantSigningHash: signingHash})
+
+	// chain_id, nonce, max_priority_fee_per_gas, max_fee_per_gas, gas_limit, destination, value, data, access_list,
+	// authorization_list
+	auth := &SetCodeAuthorization{ChainID: chainID, Address: to, Nonce: 2}
+	signSetCodeAuthorization(t, auth, crypto.FromECDSA(key))
+	setCodeFields := []interface{}{chainID, uint64(1), big.NewInt(1), big.NewInt(2), uint64(50000), from, big.NewInt(0), []byte{}, []interface{}{},
+		[]*SetCodeAuthorization{auth}}
+	raw, _, signingHash = signManualTransaction(t, key, SetCodeTxType, setCodeFields)
+	tests = append(tests, testCase{name: "eip7702", raw: raw, wantType: SetCodeTxType, wantChainID: chainID, wantHash: crypto.Keccak256Hash(raw), wantSigningHash: signingHash})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RecoverTransactionSender(tt.raw)
+			assert.NoError(t, err)
+			assert.Equal(t, &TransactionSender{
+				From:        from,
+				Type:        tt.wantType,
+				ChainID:     tt.wantChainID,
+				Hash:        tt.wantHash,
+				SigningHash: tt.wantSigningHash,
+			}, got)
+
+			got, err = RecoverTransactionSenderHex(hexutil.Encode(tt.raw))
+			assert.NoError(t, err)
+			assert.Equal(t, from, got.From)
+		})
+	}
+
+	_, err = RecoverTransactionSender([]byte{0x05, 0xc0})
+	assert.ErrorIs(t, err, ErrUnsupportedTransactionType)
+	_, err = RecoverTransactionSender(nil)
+	assert.Error(t, err)
+	_, err = RecoverTransactionSender([]byte{SetCodeTxType, 0xc1, 0x01})
+	assert.Error(t, err)
+}
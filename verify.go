@@ -16,24 +16,177 @@ package sigverify
 
 import (
 	"context"
+	"errors"
 
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
 // VerifySignatureEx is used to verify text signature
-func VerifySignatureEx(ctx context.Context, client *ethclient.Client, address ethcommon.Address, msg []byte, signature []byte) (bool, error) {
-	if ok, err := VerifyEllipticCurveSignatureEx(address, msg, signature); err == nil && ok {
-		return true, nil
+func VerifySignatureEx(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, msg []byte, signature []byte, opts ...VerifyOption) (bool, error) {
+	result, err := VerifySignatureExDetailed(ctx, caller, address, msg, signature, opts...)
+	return result.Valid, err
+}
+
+// VerifySignatureExDetailed is like VerifySignatureEx, but returns a VerificationResult that explains
+// how the signature was verified. When the elliptic curve verification fails, its reason is kept in
+// result.Failures instead of being discarded, and the returned error is the one of ERC1271.
+func VerifySignatureExDetailed(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, msg []byte, signature []byte, opts ...VerifyOption) (*VerificationResult, error) {
+	return verifyHashSignature(ctx, caller, address, textDigest(msg), signature, newVerifyOptions(opts))
+}
+
+// VerifyHashSignatureEx verifies a signature over a raw 32-byte digest, it recovers the signer of hash
+// with the formats supported by RecoveryAddressEx and falls back to isValidSignature(hash, signature) of ERC1271.
+// ERC6492 signatures of counterfactual wallets are validated by a deployless eth_call directly.
+// Look up WithStrategy to skip the methods that cannot succeed for the kind of account,
+// and WithDelegationPolicy for the EOAs delegated by EIP-7702.
+// The text and typed data variants are wrappers of this function.
+// It also verifies the legacy eth_sign signatures made over a raw hash without the personal message prefix,
+// which VerifyRawHashSignature is named after, use VerifyRawOrPrefixedHashSignature when the wallet may have
+// added the prefix.
+func VerifyHashSignatureEx(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, hash [32]byte, signature []byte, opts ...VerifyOption) (bool, error) {
+	result, err := VerifyHashSignatureExDetailed(ctx, caller, address, hash, signature, opts...)
+	return result.Valid, err
+}
+
+// VerifyHashHexSignatureEx is like VerifyHashSignatureEx but accepts a hex encoded signature
+func VerifyHashHexSignatureEx(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, hash [32]byte, signature string, opts ...VerifyOption) (bool, error) {
+	sig, err := HexDecode(signature)
+	if err != nil {
+		return false, err
 	}
-	return VerifyERC1271Signature(ctx, client, address, msg, signature)
+	return VerifyHashSignatureEx(ctx, caller, address, hash, sig, opts...)
+}
+
+// VerifyHashSignatureExDetailed is like VerifyHashSignatureEx, but returns a VerificationResult
+// that explains how the signature was verified.
+func VerifyHashSignatureExDetailed(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, hash [32]byte, signature []byte, opts ...VerifyOption) (*VerificationResult, error) {
+	return verifyHashSignature(ctx, caller, address, signedDigest{hash: hash}, signature, newVerifyOptions(opts))
+}
+
+// signedDigest is a digest to verify a signature against, together with the message it was hashed from if it is known,
+// which is passed to the legacy isValidSignature(bytes,bytes) of ERC1271
+type signedDigest struct {
+	hash    [32]byte
+	message []byte
+}
+
+// textDigest returns the signedDigest of the personal message msg
+func textDigest(msg []byte) signedDigest {
+	if msg == nil {
+		msg = []byte{}
+	}
+	return signedDigest{hash: ethcommon.BytesToHash(accounts.TextHash(msg)), message: msg}
+}
+
+func verifyHashSignature(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, digest signedDigest, signature []byte, options *verifyOptions) (*VerificationResult, error) {
+	result := newVerificationResult(address, digest.hash[:])
+	result.message = digest.message
+	if options.strategy == StrategyClassifyAccount || options.delegationPolicy != DelegationEither {
+		account, err := classifyAccount(ctx, caller, address, signature, options)
+		if err != nil {
+			return result, err
+		}
+		result.Account = account
+		switch {
+		case account.Type == AccountDelegated && options.delegationPolicy == DelegationECDSAOnly:
+			return result, verifyEllipticCurveDigest(result, signature, options.recovery)
+		case account.Type == AccountDelegated && options.delegationPolicy == DelegationERC1271Only:
+			return result, verifyERC1271Digest(ctx, caller, result, signature, options)
+		case options.strategy != StrategyClassifyAccount:
+		case account.Type == AccountEOA:
+			return result, verifyEllipticCurveDigest(result, signature, options.recovery)
+		case account.Type == AccountContract || account.Type == AccountUndeployed:
+			return result, verifyERC1271Digest(ctx, caller, result, signature, options)
+		}
+	}
+	if IsERC6492Signature(signature) {
+		return result, verifyERC1271Digest(ctx, caller, result, signature, options)
+	}
+	if err := verifyEllipticCurveDigest(result, signature, options.recovery); err == nil && result.Valid {
+		return result, nil
+	}
+	return result, verifyERC1271Digest(ctx, caller, result, signature, options)
+}
+
+// verifyHashCandidates verifies signature against each of digests, which are the different ways the
+// signed data may have been encoded, and returns the index of the digest that the signature is valid for, or -1.
+// With the default strategy and delegation policy, the elliptic curve verification of every hash is tried before
+// any contract is called, so that an EOA never costs an eth_call, and ERC1271 is then probed for every digest without
+// verifying the elliptic curve again. The probing stops at ErrNoContractCode, which holds for every digest.
+// The result and error of the last digest tried are returned when none is valid.
+func verifyHashCandidates(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, digests []signedDigest, signature []byte, options *verifyOptions) (*VerificationResult, int, error) {
+	var curveResults []*VerificationResult
+	if options.strategy == StrategyECDSAFirst && options.delegationPolicy == DelegationEither && !IsERC6492Signature(signature) {
+		for i, digest := range digests {
+			result := newVerificationResult(address, digest.hash[:])
+			result.message = digest.message
+			if err := verifyEllipticCurveDigest(result, signature, options.recovery); err == nil && result.Valid {
+				return result, i, nil
+			}
+			curveResults = append(curveResults, result)
+		}
+	}
+	var (
+		result = newVerificationResult(address, nil)
+		err    error
+	)
+	for i, digest := range digests {
+		if curveResults != nil {
+			result = curveResults[i]
+			err = verifyERC1271Digest(ctx, caller, result, signature, options)
+		} else {
+			result, err = verifyHashSignature(ctx, caller, address, digest, signature, options)
+		}
+		if err == nil && result.Valid {
+			return result, i, nil
+		}
+		if errors.Is(err, ErrNoContractCode) {
+			break
+		}
+	}
+	return result, -1, err
 }
 
 // VerifyHexSignatureEx is used to verify text signature
-func VerifyHexSignatureEx(ctx context.Context, client *ethclient.Client, address ethcommon.Address, msg []byte, signature string) (bool, error) {
+func VerifyHexSignatureEx(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, msg []byte, signature string, opts ...VerifyOption) (bool, error) {
 	sigBytes, err := HexDecode(signature)
 	if err != nil {
 		return false, err
 	}
-	return VerifySignatureEx(ctx, client, address, msg, sigBytes)
+	return VerifySignatureEx(ctx, caller, address, msg, sigBytes, opts...)
+}
+
+// VerifyParsedSignatureEx is like VerifySignatureEx but accepts a parsed Signature
+func VerifyParsedSignatureEx(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, msg []byte, signature Signature, opts ...VerifyOption) (bool, error) {
+	return VerifySignatureEx(ctx, caller, address, msg, signature.Bytes(), opts...)
+}
+
+// VerifyTypedDataSignatureWithFallback is the counterpart of VerifySignatureEx for EIP-712 typed data,
+// it tries the elliptic curve verification first and falls back to ERC1271 with the typed data hash.
+// VerifyTypedDataSignatureEx only does the elliptic curve verification.
+func VerifyTypedDataSignatureWithFallback(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, data apitypes.TypedData, signature []byte, opts ...VerifyOption) (bool, error) {
+	result, err := VerifyTypedDataSignatureWithFallbackDetailed(ctx, caller, address, data, signature, opts...)
+	return result.Valid, err
+}
+
+// VerifyTypedDataSignatureWithFallbackDetailed is like VerifyTypedDataSignatureWithFallback, but returns a VerificationResult
+// that explains how the signature was verified.
+func VerifyTypedDataSignatureWithFallbackDetailed(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, data apitypes.TypedData, signature []byte, opts ...VerifyOption) (*VerificationResult, error) {
+	_, dataHash, err := HashTypedData(data)
+	if err != nil {
+		return &VerificationResult{Address: address}, err
+	}
+	return VerifyHashSignatureExDetailed(ctx, caller, address, ethcommon.BytesToHash(dataHash), signature, opts...)
+}
+
+// VerifyTypedDataHexSignatureWithFallback is like VerifyTypedDataSignatureWithFallback but accepts a hex encoded signature
+func VerifyTypedDataHexSignatureWithFallback(ctx context.Context, caller bind.ContractCaller, address ethcommon.Address, data apitypes.TypedData, signature string, opts ...VerifyOption) (bool, error) {
+	sig, err := HexDecode(signature)
+	if err != nil {
+		return false, err
+	}
+	return VerifyTypedDataSignatureWithFallback(ctx, caller, address, data, sig, opts...)
 }
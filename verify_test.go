@@ -19,17 +19,18 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestVerifyHexSignatureEx(t *testing.T) {
-	client, err := ethclient.Dial("https://polygon-rpc.com")
-	assert.Equal(t, nil, err)
+	wallets := newSimulatedWallets(t)
 	type args struct {
 		ctx       context.Context
-		client    *ethclient.Client
+		caller    bind.ContractCaller
 		address   string
 		msg       []byte
 		signature string
@@ -44,7 +45,7 @@ func TestVerifyHexSignatureEx(t *testing.T) {
 			name: "curve",
 			args: args{
 				ctx:       context.Background(),
-				client:    client,
+				caller:    wallets.backend,
 				address:   "0xb052C02346F80cF6ae4DF52c10FABD3e0aD24d81",
 				msg:       []byte("hello"),
 				signature: "0x0498c6564863c78e663848b963fde1ea1d860d5d882d2abdb707d1e9179ff80630a4a71705da534a562c08cb64a546c6132de26eb77a44f086832cbc1dbe01f71b",
@@ -56,7 +57,7 @@ func TestVerifyHexSignatureEx(t *testing.T) {
 			name: "ledger",
 			args: args{
 				ctx:       context.Background(),
-				client:    client,
+				caller:    wallets.backend,
 				address:   "0x545087bd36c7F0eFaeC26252Ee62085CA9A726AC",
 				msg:       []byte("abc"),
 				signature: "0xb6a1ef0b63715a4d303e3935e4a6c75c89ead4311c089e98082e7eaf7e4b460a19e998df7c9ad308e4e5db376364b9e5e4b6f75c4628452cedd13641d1099c8e00",
@@ -68,22 +69,94 @@ func TestVerifyHexSignatureEx(t *testing.T) {
 			name: "erc1271/without 0x prefix",
 			args: args{
 				ctx:       context.Background(),
-				client:    client,
-				address:   "0x4836A472ab1dd406ECb8D0F933A985541ee3921f",
+				caller:    wallets.backend,
+				address:   wallets.owner.Hex(),
 				msg:       MustMustHexDecode(t, "787177"),
-				signature: "c0f8db6019888d87a0afc1299e81ef45d3abce64f63072c8d7a6ef00f5f82c1522958ff110afa98b8c0d23b558376db1d2fbab4944e708f8bf6dc7b977ee07201b00",
+				signature: hexutil.Encode(wallets.sign(t, MustMustHexDecode(t, "787177")))[2:],
 			},
 			want:    true,
 			wantErr: assert.NoError,
 		},
+		{
+			name: "erc1271/wrong magic",
+			args: args{
+				ctx:       context.Background(),
+				caller:    wallets.backend,
+				address:   wallets.wrongMagic.Hex(),
+				msg:       []byte("hello"),
+				signature: "0x0498c6564863c78e663848b963fde1ea1d860d5d882d2abdb707d1e9179ff80630a4a71705da534a562c08cb64a546c6132de26eb77a44f086832cbc1dbe01f71b",
+			},
+			want:    false,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "eoa/signed by another key",
+			args: args{
+				ctx:       context.Background(),
+				caller:    wallets.backend,
+				address:   wallets.eoa.Hex(),
+				msg:       []byte("hello"),
+				signature: "0x0498c6564863c78e663848b963fde1ea1d860d5d882d2abdb707d1e9179ff80630a4a71705da534a562c08cb64a546c6132de26eb77a44f086832cbc1dbe01f71b",
+			},
+			want: false,
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.True(t, IsErrNoContractCode(err), i...)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := VerifyHexSignatureEx(tt.args.ctx, tt.args.client, common.HexToAddress(tt.args.address), tt.args.msg, tt.args.signature)
-			if !tt.wantErr(t, err, fmt.Sprintf("VerifyHexSignatureEx(%v, %v, %v, %v, %v)", tt.args.ctx, tt.args.client, tt.args.address, tt.args.msg, tt.args.signature)) {
+			got, err := VerifyHexSignatureEx(tt.args.ctx, tt.args.caller, common.HexToAddress(tt.args.address), tt.args.msg, tt.args.signature)
+			if !tt.wantErr(t, err, fmt.Sprintf("VerifyHexSignatureEx(%v, %v, %v, %v, %v)", tt.args.ctx, tt.args.caller, tt.args.address, tt.args.msg, tt.args.signature)) {
 				return
 			}
-			assert.Equalf(t, tt.want, got, "VerifyHexSignatureEx(%v, %v, %v, %v, %v)", tt.args.ctx, tt.args.client, tt.args.address, tt.args.msg, tt.args.signature)
+			assert.Equalf(t, tt.want, got, "VerifyHexSignatureEx(%v, %v, %v, %v, %v)", tt.args.ctx, tt.args.caller, tt.args.address, tt.args.msg, tt.args.signature)
 		})
 	}
 }
+
+func TestVerifyHashSignatureEx(t *testing.T) {
+	wallets := newSimulatedWallets(t)
+	hash := crypto.Keccak256Hash([]byte("custom hashing scheme"))
+	signature := wallets.signHash(t, hash[:])
+	tests := []struct {
+		name       string
+		address    common.Address
+		want       bool
+		wantMethod VerificationMethod
+	}{
+		{
+			name:       "ecdsa",
+			address:    wallets.eoa,
+			want:       true,
+			wantMethod: MethodECDSA,
+		},
+		{
+			name:       "erc1271",
+			address:    wallets.owner,
+			want:       true,
+			wantMethod: MethodERC1271,
+		},
+		{
+			name:    "erc1271/wrong magic",
+			address: wallets.wrongMagic,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := VerifyHashSignatureExDetailed(context.Background(), wallets.backend, tt.address, hash, signature)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result.Valid)
+			assert.Equal(t, tt.wantMethod, result.Method)
+			assert.Equal(t, hash, result.Digest)
+
+			valid, err := VerifyHashHexSignatureEx(context.Background(), wallets.backend, tt.address, hash, hexutil.Encode(signature))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, valid)
+		})
+	}
+
+	valid, err := VerifyERC1271Hash(context.Background(), wallets.backend, wallets.owner, hash, signature)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}